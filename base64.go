@@ -0,0 +1,24 @@
+package jzon
+
+import (
+	"encoding/base64"
+	"fmt"
+)
+
+// ParseBase64 decodes an String json value by enc, like base64.StdEncoding
+// or base64.URLEncoding. base64.StdEncoding is used if enc is nil.
+func (json *JSON) ParseBase64(enc *base64.Encoding) ([]byte, error) {
+	if enc == nil {
+		enc = base64.StdEncoding
+	}
+	b, err := json.ParseBytes(nil)
+	if err != nil {
+		return nil, fmt.Errorf("ParseBase64: %v", err)
+	}
+	dst := make([]byte, enc.DecodedLen(len(b)))
+	n, err := enc.Decode(dst, b)
+	if err != nil {
+		return nil, err
+	}
+	return dst[:n], nil
+}
@@ -0,0 +1,36 @@
+package jzon
+
+import (
+	"encoding/base64"
+	"testing"
+)
+
+func TestJSON_ParseBase64(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		enc     *base64.Encoding
+		want    string
+		wantErr bool
+	}{
+		{"1", `"aGVsbG8/Pz4+"`, nil, "hello??>>", false},
+		{"2", `"aGVsbG8_Pz4-"`, base64.URLEncoding, "hello??>>", false},
+		{"3", `"aGVsbG8\/Pz4+"`, base64.StdEncoding, "hello??>>", false},
+		{"4", `"aGVsbG8_Pz4-"`, base64.StdEncoding, "", true},
+		{"5", `"aGk"`, base64.RawStdEncoding, "hi", false},
+		{"6", `""`, nil, "", false},
+		{"7", `null`, nil, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FromString(tt.data).ParseBase64(tt.enc)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("JSON.ParseBase64() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if string(got) != tt.want {
+				t.Errorf("JSON.ParseBase64() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
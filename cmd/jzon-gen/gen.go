@@ -0,0 +1,354 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"strings"
+	"unicode"
+
+	"github.com/zoumo/jzon"
+)
+
+// commonInitialisms are written in upper case in Go names
+var commonInitialisms = map[string]bool{
+	"API": true, "ASCII": true, "CPU": true, "CSS": true, "DNS": true,
+	"HTML": true, "HTTP": true, "HTTPS": true, "ID": true, "IP": true,
+	"JSON": true, "OS": true, "SQL": true, "TCP": true, "TLS": true,
+	"TTL": true, "UDP": true, "UI": true, "URI": true, "URL": true,
+	"UUID": true, "XML": true,
+}
+
+// generator generates Go type declarations from a JSON Schema, only
+// type, format, properties, required and items are used
+type generator struct {
+	// decls are the struct declarations, a parent is before its children
+	decls []string
+	// names are the declared type names
+	names map[string]bool
+}
+
+// Generate returns the formatted Go source of package pkg, which declares
+// the type name described by schema and its nested types
+func Generate(schema *jzon.JSON, pkg, name string) ([]byte, error) {
+	name = exportName(name)
+	g := &generator{names: make(map[string]bool)}
+	if !isStruct(schema) {
+		// the root is declared as a named type, the nested
+		// structs can not use its name
+		g.uniqueName(name)
+	}
+	typ, err := g.goType(schema, name)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by jzon-gen. DO NOT EDIT.\n\npackage %s\n", pkg)
+	if typ != name {
+		// the root is not a struct, declare it as a named type
+		fmt.Fprintf(&buf, "\ntype %s %s\n", name, typ)
+	}
+	for _, decl := range g.decls {
+		buf.WriteString("\n")
+		buf.WriteString(decl)
+	}
+	return format.Source(buf.Bytes())
+}
+
+// goType returns the Go type of schema, the struct of object is
+// declared as name. Nullable value is not a pointer, it is decided
+// by the field.
+func (g *generator) goType(schema *jzon.JSON, name string) (string, error) {
+	types, _, err := schemaTypes(schema)
+	if err != nil {
+		return "", err
+	}
+	if len(types) != 1 {
+		return "interface{}", nil
+	}
+	switch types[0] {
+	case "string":
+		return "string", nil
+	case "integer":
+		// integers like 1.0, 1e3 or the ones beyond int64 can not be
+		// decoded into int64, jzon.InferSchema adds format int64 if
+		// all of them can
+		if schema.GetString("", "format") == "int64" {
+			return "int64", nil
+		}
+		return "float64", nil
+	case "number":
+		return "float64", nil
+	case "boolean":
+		return "bool", nil
+	case "array":
+		items, ok := member(schema, "items")
+		if !ok {
+			return "[]interface{}", nil
+		}
+		elem, err := g.goType(items, singular(name))
+		if err != nil {
+			return "", err
+		}
+		return "[]" + elem, nil
+	case "object":
+		if !hasProperties(schema) {
+			return "map[string]interface{}", nil
+		}
+		return g.declareStruct(schema, name)
+	}
+	return "interface{}", nil
+}
+
+// declareStruct declares the struct of object schema, and returns
+// the type name which is unique in the generated source
+func (g *generator) declareStruct(schema *jzon.JSON, name string) (string, error) {
+	name = g.uniqueName(name)
+	// reserve the place so the parent is declared before children
+	index := len(g.decls)
+	g.decls = append(g.decls, "")
+
+	required := make(map[string]bool)
+	if list, ok := member(schema, "required"); ok {
+		iter, err := list.Array()
+		if err != nil {
+			return "", err
+		}
+		for iter.Next() {
+			key, err := iter.Value().ParseString()
+			if err != nil {
+				return "", err
+			}
+			required[key] = true
+		}
+	}
+
+	properties, _ := member(schema, "properties")
+	iter, err := properties.Object()
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "type %s struct {\n", name)
+	fields := make(map[string]bool)
+	for iter.Next() {
+		key := iter.Key()
+		if !validTagName(key) {
+			// encoding/json ignores the tag with such name, the key
+			// would be decoded into a field of other name
+			fmt.Fprintf(&buf, "\t// key %q is skipped, it can not be a json tag name\n", key)
+			continue
+		}
+		prop := iter.Value()
+		typ, err := g.goType(prop, exportName(key))
+		if err != nil {
+			return "", err
+		}
+		_, nullable, err := schemaTypes(prop)
+		if err != nil {
+			return "", err
+		}
+		optional := !required[key]
+		if (optional || nullable) && !isNilable(typ) {
+			typ = "*" + typ
+		}
+		tag := key
+		if optional {
+			tag += ",omitempty"
+		}
+		if tag == "-" {
+			// "-" means the field is ignored by encoding/json
+			tag = "-,"
+		}
+		fmt.Fprintf(&buf, "\t%s %s `json:%q`\n", uniqueField(fields, exportName(key)), typ, tag)
+	}
+	if iter.Err() != nil {
+		return "", iter.Err()
+	}
+	buf.WriteString("}\n")
+	g.decls[index] = buf.String()
+	return name, nil
+}
+
+// uniqueName returns name, or name with a number suffix if it is used
+func (g *generator) uniqueName(name string) string {
+	unique := name
+	for i := 2; g.names[unique]; i++ {
+		unique = fmt.Sprintf("%s%d", name, i)
+	}
+	g.names[unique] = true
+	return unique
+}
+
+func uniqueField(fields map[string]bool, name string) string {
+	unique := name
+	for i := 2; fields[unique]; i++ {
+		unique = fmt.Sprintf("%s%d", name, i)
+	}
+	fields[unique] = true
+	return unique
+}
+
+// schemaTypes returns the types of schema except null, and whether
+// null is one of the types
+func schemaTypes(schema *jzon.JSON) ([]string, bool, error) {
+	if schema.Kind() != jzon.Object {
+		// boolean schema allows any value
+		return nil, false, nil
+	}
+	v, ok := member(schema, "type")
+	if !ok {
+		return nil, false, nil
+	}
+	var all []string
+	switch v.Kind() {
+	case jzon.String:
+		t, err := v.ParseString()
+		if err != nil {
+			return nil, false, err
+		}
+		all = []string{t}
+	case jzon.Array:
+		iter, err := v.Array()
+		if err != nil {
+			return nil, false, err
+		}
+		for iter.Next() {
+			t, err := iter.Value().ParseString()
+			if err != nil {
+				return nil, false, err
+			}
+			all = append(all, t)
+		}
+	default:
+		return nil, false, fmt.Errorf("type of schema must be a string or an array, got %s", v.Kind())
+	}
+
+	var types []string
+	nullable := false
+	for _, t := range all {
+		if t == "null" {
+			nullable = true
+			continue
+		}
+		types = append(types, t)
+	}
+	// integer is a number, so they can be merged
+	if len(types) == 2 && (types[0] == "integer" && types[1] == "number" || types[0] == "number" && types[1] == "integer") {
+		types = []string{"number"}
+	}
+	return types, nullable, nil
+}
+
+// member returns the value of key in object schema
+func member(schema *jzon.JSON, key string) (*jzon.JSON, bool) {
+	if schema.Kind() != jzon.Object {
+		return nil, false
+	}
+	v := schema.Clone()
+	if v.Path(key) != nil {
+		return nil, false
+	}
+	return v, true
+}
+
+// isStruct reports whether schema is declared as a struct
+func isStruct(schema *jzon.JSON) bool {
+	types, _, err := schemaTypes(schema)
+	return err == nil && len(types) == 1 && types[0] == "object" && hasProperties(schema)
+}
+
+// hasProperties reports whether the object schema has any properties,
+// the object without properties is a map
+func hasProperties(schema *jzon.JSON) bool {
+	properties, ok := member(schema, "properties")
+	if !ok || properties.Kind() != jzon.Object {
+		return false
+	}
+	iter, err := properties.Object()
+	return err == nil && iter.Next()
+}
+
+// validTagName reports whether key can be the name in json tag, it is the
+// same rule as encoding/json
+func validTagName(key string) bool {
+	if key == "" {
+		return false
+	}
+	for _, r := range key {
+		switch {
+		case strings.ContainsRune("!#$%&()*+-./:;<=>?@[]^_{|}~ ", r):
+			// backquote, quote, backslash and comma are not allowed
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			return false
+		}
+	}
+	return true
+}
+
+func isNilable(typ string) bool {
+	return typ == "interface{}" || strings.HasPrefix(typ, "[]") || strings.HasPrefix(typ, "map[")
+}
+
+// exportName converts key to an exported Go name,
+// like "avatar_url" to "AvatarURL"
+func exportName(key string) string {
+	var words []string
+	var word []rune
+	flush := func() {
+		if len(word) > 0 {
+			words = append(words, string(word))
+			word = word[:0]
+		}
+	}
+	runes := []rune(key)
+	for i, r := range runes {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			flush()
+		case unicode.IsUpper(r) && len(word) > 0 &&
+			(unicode.IsLower(word[len(word)-1]) || i+1 < len(runes) && unicode.IsLower(runes[i+1])):
+			// split camelCase and the end of acronym like "HTTPServer"
+			flush()
+			word = append(word, r)
+		default:
+			word = append(word, r)
+		}
+	}
+	flush()
+
+	var b strings.Builder
+	for _, w := range words {
+		if upper := strings.ToUpper(w); commonInitialisms[upper] {
+			b.WriteString(upper)
+			continue
+		}
+		r := []rune(w)
+		b.WriteRune(unicode.ToUpper(r[0]))
+		b.WriteString(string(r[1:]))
+	}
+	name := b.String()
+	if name == "" {
+		return "Field"
+	}
+	// the name starts with digit or the letter without case
+	// is not exported
+	if !unicode.IsUpper([]rune(name)[0]) {
+		return "X" + name
+	}
+	return name
+}
+
+// singular returns the singular form of the plural name in English,
+// the name of array element is derived from it
+func singular(name string) string {
+	switch {
+	case strings.HasSuffix(name, "ies") && len(name) > 3:
+		return name[:len(name)-3] + "y"
+	case strings.HasSuffix(name, "sses"), strings.HasSuffix(name, "xes"), strings.HasSuffix(name, "ches"), strings.HasSuffix(name, "shes"):
+		return name[:len(name)-2]
+	case strings.HasSuffix(name, "s") && !strings.HasSuffix(name, "ss") && len(name) > 1:
+		return name[:len(name)-1]
+	}
+	return name + "Item"
+}
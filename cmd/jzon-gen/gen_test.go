@@ -0,0 +1,207 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/zoumo/jzon"
+)
+
+func TestGenerate(t *testing.T) {
+	samples := []*jzon.JSON{
+		jzon.FromString(`{"id": 1, "user_name": "a", "avatar_url": null, "tags": ["x"], "profile": {"age": 1.5}, "groups": [{"id": 1}]}`),
+		jzon.FromString(`{"id": 2, "user_name": "b", "avatar_url": "/a.png", "tags": [], "extra": {}, "groups": []}`),
+	}
+	schema, err := jzon.InferSchema(samples...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := Generate(schema, "api", "user")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "// Code generated by jzon-gen. DO NOT EDIT.\n" +
+		"\n" +
+		"package api\n" +
+		"\n" +
+		"type User struct {\n" +
+		"\tID        int64                  `json:\"id\"`\n" +
+		"\tUserName  string                 `json:\"user_name\"`\n" +
+		"\tAvatarURL *string                `json:\"avatar_url\"`\n" +
+		"\tTags      []string               `json:\"tags\"`\n" +
+		"\tProfile   *Profile               `json:\"profile,omitempty\"`\n" +
+		"\tGroups    []Group                `json:\"groups\"`\n" +
+		"\tExtra     map[string]interface{} `json:\"extra,omitempty\"`\n" +
+		"}\n" +
+		"\n" +
+		"type Profile struct {\n" +
+		"\tAge float64 `json:\"age\"`\n" +
+		"}\n" +
+		"\n" +
+		"type Group struct {\n" +
+		"\tID int64 `json:\"id\"`\n" +
+		"}\n"
+	if string(got) != want {
+		t.Errorf("Generate() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestGenerate_Schema(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema string
+		want   string
+	}{
+		{
+			"array root",
+			`{"type": "array", "items": {"type": "object", "properties": {"a": {"type": ["integer", "number"]}}}}`,
+			"type Root []RootItem\n\ntype RootItem struct {\n\tA *float64 `json:\"a,omitempty\"`\n}\n",
+		},
+		{
+			"scalar root",
+			`{"type": "string"}`,
+			"type Root string\n",
+		},
+		{
+			"any",
+			`{"type": "object", "properties": {"a": true, "b": {"type": ["string", "integer"]}, "c": {"type": "array"}}, "required": ["a", "b", "c"]}`,
+			"type Root struct {\n\tA interface{}   `json:\"a\"`\n\tB interface{}   `json:\"b\"`\n\tC []interface{} `json:\"c\"`\n}\n",
+		},
+		{
+			"name conflicts",
+			`{"type": "object", "properties": {"root": {"type": "object", "properties": {"Root": {"type": "object", "properties": {"a": {"type": "boolean"}}}}}, "a-b": {"type": "null"}, "a_b": {"type": "object", "properties": {}}}}`,
+			"type Root struct {\n\tRoot *Root2                 `json:\"root,omitempty\"`\n\tAB   interface{}            `json:\"a-b,omitempty\"`\n\tAB2  map[string]interface{} `json:\"a_b,omitempty\"`\n}\n\ntype Root2 struct {\n\tRoot *Root3 `json:\"Root,omitempty\"`\n}\n\ntype Root3 struct {\n\tA *bool `json:\"a,omitempty\"`\n}\n",
+		},
+		{
+			"dash key",
+			`{"type": "object", "properties": {"-": {"type": "string"}, "a": {"type": "object", "properties": {"-": {"type": "string"}}}}, "required": ["-"]}`,
+			"type Root struct {\n\tField string `json:\"-,\"`\n\tA     *A     `json:\"a,omitempty\"`\n}\n\ntype A struct {\n\tField *string `json:\"-,omitempty\"`\n}\n",
+		},
+		{
+			"integer format",
+			`{"type": "object", "properties": {"a": {"type": "integer", "format": "int64"}, "b": {"type": "integer"}}, "required": ["a", "b"]}`,
+			"type Root struct {\n\tA int64   `json:\"a\"`\n\tB float64 `json:\"b\"`\n}\n",
+		},
+		{
+			"invalid tag names",
+			`{"type": "object", "properties": {"a` + "`" + `b": {"type": "string"}, "a,b": {"type": "string"}, "a\"b": {"type": "string"}, "": {"type": "string"}, "a.b/c": {"type": "string"}}}`,
+			"type Root struct {\n\t// key \"a`b\" is skipped, it can not be a json tag name\n\t// key \"a,b\" is skipped, it can not be a json tag name\n\t// key \"a\\\"b\" is skipped, it can not be a json tag name\n\t// key \"\" is skipped, it can not be a json tag name\n\tABC *string `json:\"a.b/c,omitempty\"`\n}\n",
+		},
+	}
+	header := "// Code generated by jzon-gen. DO NOT EDIT.\n\npackage main\n\n"
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Generate(jzon.FromString(tt.schema), "main", "Root")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != header+tt.want {
+				t.Errorf("Generate() =\n%s\nwant\n%s", got, header+tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerate_RootName(t *testing.T) {
+	// the root is not a struct, the nested struct can not use its name
+	schema, err := jzon.InferSchema(jzon.FromString(`[{"root": {"a": 1}}]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := Generate(schema, "main", "Root")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "// Code generated by jzon-gen. DO NOT EDIT.\n" +
+		"\n" +
+		"package main\n" +
+		"\n" +
+		"type Root []RootItem\n" +
+		"\n" +
+		"type RootItem struct {\n" +
+		"\tRoot Root2 `json:\"root\"`\n" +
+		"}\n" +
+		"\n" +
+		"type Root2 struct {\n" +
+		"\tA int64 `json:\"a\"`\n" +
+		"}\n"
+	if string(got) != want {
+		t.Errorf("Generate() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestGenerate_Numbers(t *testing.T) {
+	tests := []struct {
+		samples []string
+		want    string
+	}{
+		{[]string{`1`, `-2`, `9223372036854775807`}, "int64"},
+		{[]string{`1`, `10.0`}, "float64"},
+		{[]string{`1`, `1e3`}, "float64"},
+		{[]string{`1`, `9223372036854775808`}, "float64"},
+		{[]string{`1.5`, `1e400`}, "float64"},
+	}
+	for _, tt := range tests {
+		var samples []*jzon.JSON
+		for _, s := range tt.samples {
+			samples = append(samples, jzon.FromString(s))
+		}
+		schema, err := jzon.InferSchema(samples...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := Generate(schema, "main", "Root")
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := "// Code generated by jzon-gen. DO NOT EDIT.\n\npackage main\n\ntype Root " + tt.want + "\n"
+		if string(got) != want {
+			t.Errorf("Generate() of %v =\n%s\nwant\n%s", tt.samples, got, want)
+		}
+	}
+}
+
+func Test_exportName(t *testing.T) {
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{"id", "ID"},
+		{"user_id", "UserID"},
+		{"avatar_template", "AvatarTemplate"},
+		{"more-topics-url", "MoreTopicsURL"},
+		{"userName", "UserName"},
+		{"HTTPServer", "HTTPServer"},
+		{"apiKey", "APIKey"},
+		{"2fa", "X2fa"},
+		{"$ref", "Ref"},
+		{"", "Field"},
+		{"名字", "X名字"},
+		{"éclair", "Éclair"},
+	}
+	for _, tt := range tests {
+		if got := exportName(tt.key); got != tt.want {
+			t.Errorf("exportName(%q) = %v, want %v", tt.key, got, tt.want)
+		}
+	}
+}
+
+func Test_singular(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"Users", "User"},
+		{"Categories", "Category"},
+		{"Addresses", "Address"},
+		{"Boxes", "Box"},
+		{"Matches", "Match"},
+		{"Status", "Statu"},
+		{"Class", "ClassItem"},
+		{"Data", "DataItem"},
+	}
+	for _, tt := range tests {
+		if got := singular(tt.name); got != tt.want {
+			t.Errorf("singular(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+// Command jzon-gen generates Go struct declarations from sample JSON
+// documents or a JSON Schema.
+//
+// Usage:
+//
+//	jzon-gen [flags] [file ...]
+//
+// The samples are read from files, or stdin if no file is given, and the
+// schema of them is inferred by jzon.InferSchema. Use -schema if the
+// input is a JSON Schema, like the one printed by -print-schema.
+//
+// The types are named from keys, objects are declared as structs with
+// json tags, arrays are []T, optional and nullable fields are pointers.
+//
+//	jzon-gen -type Topics -package discourse latest.json > topics.go
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/zoumo/jzon"
+)
+
+func main() {
+	var (
+		typeName    = flag.String("type", "Root", "name of the root type")
+		pkg         = flag.String("package", "main", "package name of the generated source")
+		output      = flag.String("o", "", "output file, default is stdout")
+		isSchema    = flag.Bool("schema", false, "the input is a JSON Schema instead of samples")
+		printSchema = flag.Bool("print-schema", false, "print the inferred JSON Schema instead of Go source")
+	)
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: jzon-gen [flags] [file ...]\n\nFlags:\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if err := run(flag.Args(), *typeName, *pkg, *output, *isSchema, *printSchema); err != nil {
+		fmt.Fprintln(os.Stderr, "jzon-gen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(files []string, typeName, pkg, output string, isSchema, printSchema bool) error {
+	inputs, err := readInputs(files)
+	if err != nil {
+		return err
+	}
+
+	var schema *jzon.JSON
+	if isSchema {
+		if len(inputs) != 1 {
+			return fmt.Errorf("-schema requires exactly one input, got %d", len(inputs))
+		}
+		schema = inputs[0]
+		if err := schema.CheckValid(); err != nil {
+			return err
+		}
+	} else {
+		if schema, err = jzon.InferSchema(inputs...); err != nil {
+			return err
+		}
+	}
+
+	var src []byte
+	if printSchema {
+		src = append(schema.RawMessage(), '\n')
+	} else if src, err = Generate(schema, pkg, typeName); err != nil {
+		return err
+	}
+
+	if output == "" {
+		_, err = os.Stdout.Write(src)
+		return err
+	}
+	return ioutil.WriteFile(output, src, 0644)
+}
+
+func readInputs(files []string) ([]*jzon.JSON, error) {
+	if len(files) == 0 {
+		json, err := jzon.FromReader(os.Stdin)
+		if err != nil {
+			return nil, err
+		}
+		return []*jzon.JSON{json}, nil
+	}
+	var inputs []*jzon.JSON
+	for _, file := range files {
+		json, err := readFile(file)
+		if err != nil {
+			return nil, err
+		}
+		inputs = append(inputs, json)
+	}
+	return inputs, nil
+}
+
+func readFile(file string) (*jzon.JSON, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return jzon.FromReader(f)
+}
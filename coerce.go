@@ -0,0 +1,260 @@
+package jzon
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// The As* methods coerce the json value between String, Number and Bool
+// for loosely typed payloads where "42", 42 and 42.0 mean the same thing.
+// The rules are
+//
+//	AsInt:    Number is converted if it is an integer in any notation,
+//	          otherwise it is truncated toward zero. String is trimmed and
+//	          parsed as a JSON number. Bool is 1 or 0.
+//	AsFloat:  Number is converted. String is trimmed and parsed as a JSON
+//	          number. Bool is 1 or 0.
+//	AsString: String is unquoted. Number is returned as it appears in the
+//	          document. Bool is "true" or "false".
+//	AsBool:   Bool is converted. Number is true if it is not zero. String
+//	          is trimmed and parsed by strconv.ParseBool, or as a number.
+//
+// Null, Object, Array and invalid json can not be coerced, an error is
+// returned.
+//
+// The Get* methods look up the value by keys like Path and coerce it, the
+// default value is returned if the path is missing, the value is null or
+// it can not be coerced. They never panic and do not move json.
+
+// AsInt coerces the json value to int64
+func (json *JSON) AsInt() (int64, error) {
+	kind := json.coerceKind()
+	switch kind {
+	case Bool:
+		b, err := json.ParseBoolean()
+		if err != nil {
+			return 0, err
+		}
+		if b {
+			return 1, nil
+		}
+		return 0, nil
+	case Number, String:
+		n, err := json.coerceNumber("AsInt")
+		if err != nil {
+			return 0, err
+		}
+		i, err := n.ParseInt64()
+		if err == nil {
+			return i, nil
+		}
+		if !isNumError(err, strconv.ErrSyntax) {
+			return 0, renameNumError(err, "AsInt")
+		}
+		// not an integer, truncate it
+		f, err := n.ParseFloat()
+		if err != nil {
+			return 0, renameNumError(err, "AsInt")
+		}
+		if f < math.MinInt64 || f >= math.MaxInt64 {
+			return 0, numError("AsInt", n.data, strconv.ErrRange)
+		}
+		return int64(f), nil
+	}
+	return 0, fmt.Errorf("AsInt: Can not coerce %s JSON to int", kind)
+}
+
+// AsFloat coerces the json value to float64
+func (json *JSON) AsFloat() (float64, error) {
+	kind := json.coerceKind()
+	switch kind {
+	case Bool:
+		b, err := json.ParseBoolean()
+		if err != nil {
+			return 0, err
+		}
+		if b {
+			return 1, nil
+		}
+		return 0, nil
+	case Number, String:
+		n, err := json.coerceNumber("AsFloat")
+		if err != nil {
+			return 0, err
+		}
+		f, err := n.ParseFloat()
+		if err != nil {
+			return 0, renameNumError(err, "AsFloat")
+		}
+		return f, nil
+	}
+	return 0, fmt.Errorf("AsFloat: Can not coerce %s JSON to float", kind)
+}
+
+// AsString coerces the json value to string
+func (json *JSON) AsString() (string, error) {
+	kind := json.coerceKind()
+	switch kind {
+	case String:
+		return json.ParseString()
+	case Number:
+		b, _, err := json.number("AsString", "string")
+		if err != nil {
+			return "", err
+		}
+		return string(b), nil
+	case Bool:
+		b, err := json.ParseBoolean()
+		if err != nil {
+			return "", err
+		}
+		return strconv.FormatBool(b), nil
+	}
+	return "", fmt.Errorf("AsString: Can not coerce %s JSON to string", kind)
+}
+
+// AsBool coerces the json value to bool
+func (json *JSON) AsBool() (bool, error) {
+	kind := json.coerceKind()
+	switch kind {
+	case Bool:
+		return json.ParseBoolean()
+	case String:
+		s, err := json.ParseBytes(nil)
+		if err != nil {
+			return false, err
+		}
+		if b, err := strconv.ParseBool(string(bytes.TrimSpace(s))); err == nil {
+			return b, nil
+		}
+		f, err := json.AsFloat()
+		if err != nil {
+			return false, fmt.Errorf("AsBool: Can not coerce String %q to bool", s)
+		}
+		return f != 0, nil
+	case Number:
+		f, err := json.AsFloat()
+		if err != nil {
+			return false, err
+		}
+		return f != 0, nil
+	}
+	return false, fmt.Errorf("AsBool: Can not coerce %s JSON to bool", kind)
+}
+
+// GetInt returns the value at keys coerced by AsInt, or def
+func (json *JSON) GetInt(def int64, keys ...interface{}) int64 {
+	v, ok := json.get(keys)
+	if !ok {
+		return def
+	}
+	i, err := v.AsInt()
+	if err != nil {
+		return def
+	}
+	return i
+}
+
+// GetFloat returns the value at keys coerced by AsFloat, or def
+func (json *JSON) GetFloat(def float64, keys ...interface{}) float64 {
+	v, ok := json.get(keys)
+	if !ok {
+		return def
+	}
+	f, err := v.AsFloat()
+	if err != nil {
+		return def
+	}
+	return f
+}
+
+// GetString returns the value at keys coerced by AsString, or def
+func (json *JSON) GetString(def string, keys ...interface{}) string {
+	v, ok := json.get(keys)
+	if !ok {
+		return def
+	}
+	s, err := v.AsString()
+	if err != nil {
+		return def
+	}
+	return s
+}
+
+// GetBool returns the value at keys coerced by AsBool, or def
+func (json *JSON) GetBool(def bool, keys ...interface{}) bool {
+	v, ok := json.get(keys)
+	if !ok {
+		return def
+	}
+	b, err := v.AsBool()
+	if err != nil {
+		return def
+	}
+	return b
+}
+
+// get returns a copy of json which is moved to keys,
+// it returns false if the path is missing or the value is null
+func (json *JSON) get(keys []interface{}) (*JSON, bool) {
+	if json == nil {
+		return nil, false
+	}
+	v := json.Clone()
+	v.err = nil
+	v.offset = v.head
+	if v.tail <= 0 {
+		v.tail = len(v.data)
+	}
+	if err := v.Path(keys...); err != nil {
+		return nil, false
+	}
+	kind := v.Kind()
+	if kind == Null || kind == Invalid {
+		return nil, false
+	}
+	return v, true
+}
+
+// coerceKind returns the kind of json, it is Invalid for nil json
+func (json *JSON) coerceKind() Kind {
+	if json == nil {
+		return Invalid
+	}
+	if json.tail <= 0 {
+		json.tail = len(json.data)
+	}
+	return json.Kind()
+}
+
+// coerceNumber returns a JSON of the Number json value,
+// or the number in String json value
+func (json *JSON) coerceNumber(method string) (*JSON, error) {
+	if json.Kind() == Number {
+		return json, nil
+	}
+	s, err := json.ParseBytes(nil)
+	if err != nil {
+		return nil, err
+	}
+	s = bytes.TrimSpace(s)
+	n := FromBytes(s)
+	if n.Kind() != Number {
+		return nil, numError(method, s, strconv.ErrSyntax)
+	}
+	return n, nil
+}
+
+func isNumError(err error, target error) bool {
+	e, ok := err.(*strconv.NumError)
+	return ok && e.Err == target
+}
+
+func renameNumError(err error, fn string) error {
+	if e, ok := err.(*strconv.NumError); ok {
+		e.Func = fn
+	}
+	return err
+}
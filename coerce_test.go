@@ -0,0 +1,236 @@
+package jzon
+
+import (
+	"testing"
+	"time"
+)
+
+func TestJSON_AsInt(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    int64
+		wantErr bool
+	}{
+		{"1", `42`, 42, false},
+		{"2", `42.0`, 42, false},
+		{"3", `"42"`, 42, false},
+		{"4", `" 4.2e1 "`, 42, false},
+		{"5", `42.9`, 42, false},
+		{"6", `-42.9`, -42, false},
+		{"7", `true`, 1, false},
+		{"8", `false`, 0, false},
+		{"9", `null`, 0, true},
+		{"10", `"abc"`, 0, true},
+		{"11", `""`, 0, true},
+		{"12", `1e30`, 0, true},
+		{"13", `{}`, 0, true},
+		{"14", `[]`, 0, true},
+		{"15", `"0x10"`, 0, true},
+		{"16", `9223372036854775807`, 9223372036854775807, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FromString(tt.data).AsInt()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("JSON.AsInt() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("JSON.AsInt() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSON_AsFloat(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    float64
+		wantErr bool
+	}{
+		{"1", `1.5`, 1.5, false},
+		{"2", `"1.5"`, 1.5, false},
+		{"3", `true`, 1, false},
+		{"4", `null`, 0, true},
+		{"5", `"1.5x"`, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FromString(tt.data).AsFloat()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("JSON.AsFloat() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("JSON.AsFloat() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSON_AsString(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    string
+		wantErr bool
+	}{
+		{"1", `"a\nb"`, "a\nb", false},
+		{"2", `-1.50e3`, "-1.50e3", false},
+		{"3", `true`, "true", false},
+		{"4", `false`, "false", false},
+		{"5", `null`, "", true},
+		{"6", `{"a":1}`, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FromString(tt.data).AsString()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("JSON.AsString() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("JSON.AsString() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSON_AsBool(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    bool
+		wantErr bool
+	}{
+		{"1", `true`, true, false},
+		{"2", `0`, false, false},
+		{"3", `0.5`, true, false},
+		{"4", `"true"`, true, false},
+		{"5", `"FALSE"`, false, false},
+		{"6", `"1"`, true, false},
+		{"7", `"2"`, true, false},
+		{"8", `"0.0"`, false, false},
+		{"9", `"yes"`, false, true},
+		{"10", `null`, false, true},
+		{"11", `[]`, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FromString(tt.data).AsBool()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("JSON.AsBool() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("JSON.AsBool() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSON_Get(t *testing.T) {
+	json := FromString(`{
+		"count": "42",
+		"ratio": 0.5,
+		"enabled": "true",
+		"name": 7,
+		"empty": null,
+		"list": [1, "2", 3.0],
+		"bad": "x"
+	}`)
+
+	if got := json.GetInt(-1, "count"); got != 42 {
+		t.Errorf("JSON.GetInt(count) = %v, want 42", got)
+	}
+	if got := json.GetInt(-1, "list", 1); got != 2 {
+		t.Errorf("JSON.GetInt(list, 1) = %v, want 2", got)
+	}
+	if got := json.GetInt(-1, "list", 2); got != 3 {
+		t.Errorf("JSON.GetInt(list, 2) = %v, want 3", got)
+	}
+	if got := json.GetInt(-1, "missing"); got != -1 {
+		t.Errorf("JSON.GetInt(missing) = %v, want -1", got)
+	}
+	if got := json.GetInt(-1, "empty"); got != -1 {
+		t.Errorf("JSON.GetInt(empty) = %v, want -1", got)
+	}
+	if got := json.GetInt(-1, "bad"); got != -1 {
+		t.Errorf("JSON.GetInt(bad) = %v, want -1", got)
+	}
+	if got := json.GetInt(-1, "list", 10); got != -1 {
+		t.Errorf("JSON.GetInt(list, 10) = %v, want -1", got)
+	}
+	if got := json.GetInt(-1, "count", "x"); got != -1 {
+		t.Errorf("JSON.GetInt(count, x) = %v, want -1", got)
+	}
+	if got := json.GetInt(-1, 1.5); got != -1 {
+		t.Errorf("JSON.GetInt(1.5) = %v, want -1", got)
+	}
+	if got := json.GetFloat(-1, "ratio"); got != 0.5 {
+		t.Errorf("JSON.GetFloat(ratio) = %v, want 0.5", got)
+	}
+	if got := json.GetString("none", "name"); got != "7" {
+		t.Errorf("JSON.GetString(name) = %v, want 7", got)
+	}
+	if got := json.GetString("none", "empty"); got != "none" {
+		t.Errorf("JSON.GetString(empty) = %v, want none", got)
+	}
+	if got := json.GetBool(false, "enabled"); got != true {
+		t.Errorf("JSON.GetBool(enabled) = %v, want true", got)
+	}
+	if got := json.GetBool(true, "bad"); got != true {
+		t.Errorf("JSON.GetBool(bad) = %v, want true", got)
+	}
+
+	// json is not moved by Get*
+	if got := json.GetInt(-1, "count"); got != 42 {
+		t.Errorf("JSON.GetInt(count) again = %v, want 42", got)
+	}
+	if json.Kind() != Object {
+		t.Errorf("JSON.Kind() = %v after Get, want Object", json.Kind())
+	}
+
+	var nilJSON *JSON
+	if got := nilJSON.GetInt(-1, "count"); got != -1 {
+		t.Errorf("nil JSON.GetInt() = %v, want -1", got)
+	}
+	if _, err := nilJSON.AsString(); err == nil {
+		t.Errorf("nil JSON.AsString() error = nil, want error")
+	}
+	if got := FromString(`{"a":`).GetInt(-1, "a"); got != -1 {
+		t.Errorf("broken JSON.GetInt() = %v, want -1", got)
+	}
+}
+
+func TestJSON_Get_Malformed(t *testing.T) {
+	for _, data := range []string{
+		`{"a" 1}`,
+		`{"b" 1, "a": 2}`,
+		`{"a": 1 "b": 2}`,
+		`{"x": {"a" 1}}`,
+		`{1: 2}`,
+		`{"a": [1 2]}`,
+		`[{"a" 1}]`,
+	} {
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			json := FromString(data)
+			json.GetInt(-1, "a")
+			json.GetFloat(-1, "x", "a")
+			json.GetString("", "a", 1)
+			json.GetBool(false, 0, "a")
+		}()
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("JSON.Get*() of %s does not return", data)
+		}
+	}
+	if got := FromString(`{"a" 1}`).GetInt(-1, "a"); got != -1 {
+		t.Errorf("malformed JSON.GetInt() = %v, want -1", got)
+	}
+}
@@ -0,0 +1,132 @@
+package jzon
+
+import "fmt"
+
+// each calls fn for every element of the array json value after checking
+// it strictly, the iteration stops if fn returns false
+func (json *JSON) each(method string, fn func(i int, v *JSON) bool) error {
+	v, err := json.validView()
+	if err != nil {
+		return err
+	}
+	if kind := v.Kind(); kind != Array {
+		json.err = fmt.Errorf("%s: Can not iterate %s JSON", method, kind)
+		return json.err
+	}
+	iter, err := v.UnsafeArray()
+	if err != nil {
+		return err
+	}
+	for iter.Next() {
+		if !fn(iter.Index(), iter.Value()) {
+			break
+		}
+	}
+	return iter.Err()
+}
+
+// Find returns the first element of array arr which satisfies pred,
+// or nil if not found
+func Find(arr *JSON, pred func(*JSON) bool) (*JSON, error) {
+	var found *JSON
+	err := arr.each("Find", func(_ int, v *JSON) bool {
+		if pred(v) {
+			found = v
+			return false
+		}
+		return true
+	})
+	return found, err
+}
+
+// Filter returns the elements of array arr which satisfy pred
+func Filter(arr *JSON, pred func(*JSON) bool) ([]*JSON, error) {
+	var result []*JSON
+	err := arr.each("Filter", func(_ int, v *JSON) bool {
+		if pred(v) {
+			result = append(result, v)
+		}
+		return true
+	})
+	return result, err
+}
+
+// Map converts every element of array arr by fn, the result is aligned
+// with elements. It stops at the first error returned by fn.
+func Map(arr *JSON, fn func(*JSON) (interface{}, error)) ([]interface{}, error) {
+	var result []interface{}
+	var fnErr error
+	err := arr.each("Map", func(_ int, v *JSON) bool {
+		var x interface{}
+		if x, fnErr = fn(v); fnErr != nil {
+			return false
+		}
+		result = append(result, x)
+		return true
+	})
+	if err == nil {
+		err = fnErr
+	}
+	return result, err
+}
+
+// Count returns the number of elements of array arr which satisfy pred,
+// all elements are counted if pred is nil
+func Count(arr *JSON, pred func(*JSON) bool) (int, error) {
+	n := 0
+	err := arr.each("Count", func(_ int, v *JSON) bool {
+		if pred == nil || pred(v) {
+			n++
+		}
+		return true
+	})
+	return n, err
+}
+
+// Pluck extracts the value at keys path from every element of array arr,
+// the result is aligned with elements, it is nil if the path is missing
+func Pluck(arr *JSON, keys ...interface{}) ([]*JSON, error) {
+	var result []*JSON
+	err := arr.each("Pluck", func(_ int, v *JSON) bool {
+		if v.Path(keys...) != nil {
+			v = nil
+		}
+		result = append(result, v)
+		return true
+	})
+	return result, err
+}
+
+// GroupKey is the key of group returned by GroupBy, Value is the string
+// of String, and the raw text of other kinds. The kind is a part of the
+// key, so 1 and "1" are in different groups.
+type GroupKey struct {
+	Kind  Kind
+	Value string
+}
+
+// GroupBy groups the elements of array arr by the value at keys path.
+// Elements without the path or with null value are not grouped.
+// The elements keep their order in every group.
+func GroupBy(arr *JSON, keys ...interface{}) (map[GroupKey][]*JSON, error) {
+	groups := make(map[GroupKey][]*JSON)
+	var parseErr error
+	err := arr.each("GroupBy", func(_ int, v *JSON) bool {
+		k, ok := v.get(keys)
+		if !ok {
+			return true
+		}
+		key := GroupKey{Kind: k.Kind(), Value: string(k.RawMessage())}
+		if key.Kind == String {
+			if key.Value, parseErr = k.ParseString(); parseErr != nil {
+				return false
+			}
+		}
+		groups[key] = append(groups[key], v)
+		return true
+	})
+	if err == nil {
+		err = parseErr
+	}
+	return groups, err
+}
@@ -0,0 +1,45 @@
+//go:build go1.23
+
+package jzon
+
+import "iter"
+
+// FilterSeq is the lazy version of Filter, the elements are yielded while
+// iterating the array. The errors are reported by arr.Err like Elements.
+func FilterSeq(arr *JSON, pred func(*JSON) bool) iter.Seq[*JSON] {
+	return func(yield func(*JSON) bool) {
+		for _, v := range arr.Elements() {
+			if pred(v) && !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// MapSeq is the lazy version of Map, the elements are converted by fn
+// while iterating the array. The errors are reported by arr.Err like
+// Elements.
+func MapSeq[T any](arr *JSON, fn func(*JSON) T) iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		for i, v := range arr.Elements() {
+			if !yield(i, fn(v)) {
+				return
+			}
+		}
+	}
+}
+
+// PluckSeq is the lazy version of Pluck, the value is nil if the path is
+// missing. The errors are reported by arr.Err like Elements.
+func PluckSeq(arr *JSON, keys ...interface{}) iter.Seq2[int, *JSON] {
+	return func(yield func(int, *JSON) bool) {
+		for i, v := range arr.Elements() {
+			if v.Path(keys...) != nil {
+				v = nil
+			}
+			if !yield(i, v) {
+				return
+			}
+		}
+	}
+}
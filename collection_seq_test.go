@@ -0,0 +1,88 @@
+//go:build go1.23
+
+package jzon
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFilterSeq(t *testing.T) {
+	arr := FromString(users)
+	var got []*JSON
+	for v := range FilterSeq(arr, isAdmin) {
+		got = append(got, v)
+	}
+	if err := arr.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"a", "e"}; !reflect.DeepEqual(names(got), want) {
+		t.Errorf("FilterSeq() = %v, want %v", names(got), want)
+	}
+
+	// lazy, the predicate is not called after break
+	calls := 0
+	for range FilterSeq(arr, func(v *JSON) bool {
+		calls++
+		return true
+	}) {
+		break
+	}
+	if calls != 1 {
+		t.Errorf("FilterSeq() called predicate %d times, want 1", calls)
+	}
+
+	arr = FromString(`[1, 2`)
+	for v := range FilterSeq(arr, isAdmin) {
+		t.Errorf("FilterSeq() of invalid array yielded %v", v)
+	}
+	if arr.Err() == nil {
+		t.Errorf("FilterSeq() of invalid array Err() = nil, want error")
+	}
+}
+
+func TestMapSeq(t *testing.T) {
+	arr := FromString(users)
+	var got []int64
+	for i, id := range MapSeq(arr, func(v *JSON) int64 { return v.GetInt(0, "id") }) {
+		got = append(got, id)
+		if i == 2 {
+			break
+		}
+	}
+	if want := []int64{1, 2, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("MapSeq() = %v, want %v", got, want)
+	}
+
+	arr = FromString(`[1, 2`)
+	for _, v := range MapSeq(arr, func(v *JSON) string { return v.String() }) {
+		t.Errorf("MapSeq() of invalid array yielded %v", v)
+	}
+	if arr.Err() == nil {
+		t.Errorf("MapSeq() of invalid array Err() = nil, want error")
+	}
+}
+
+func TestPluckSeq(t *testing.T) {
+	arr := FromString(users)
+	var got []*JSON
+	var indexes []int
+	for i, v := range PluckSeq(arr, "name") {
+		indexes = append(indexes, i)
+		got = append(got, v)
+		if i == 2 {
+			break
+		}
+	}
+	if want := []int{0, 1, 2}; !reflect.DeepEqual(indexes, want) {
+		t.Errorf("PluckSeq() indexes = %v, want %v", indexes, want)
+	}
+	if got[0].String() != `"a"` || got[2].String() != `"c"` {
+		t.Errorf("PluckSeq() = %v", got)
+	}
+	for i, v := range PluckSeq(arr, "age") {
+		if i == 2 && v != nil {
+			t.Errorf("PluckSeq() of missing path = %v, want nil", v)
+		}
+	}
+}
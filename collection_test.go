@@ -0,0 +1,179 @@
+package jzon
+
+import (
+	"reflect"
+	"testing"
+)
+
+var users = `[
+	{"id": 1, "name": "a", "role": "admin", "age": 30},
+	{"id": 2, "name": "b", "role": "user", "age": 20},
+	{"id": 3, "name": "c", "role": "user"},
+	{"id": 4, "name": "d", "role": null, "age": 40},
+	{"id": 5, "name": "e", "role": "admin", "age": 50}
+]`
+
+func names(values []*JSON) []string {
+	var result []string
+	for _, v := range values {
+		if v == nil {
+			result = append(result, "<nil>")
+			continue
+		}
+		result = append(result, v.GetString("", "name"))
+	}
+	return result
+}
+
+func isAdmin(v *JSON) bool {
+	return v.GetString("", "role") == "admin"
+}
+
+func TestFind(t *testing.T) {
+	got, err := Find(FromString(users), func(v *JSON) bool {
+		return v.GetInt(0, "age") > 35
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got == nil || got.GetString("", "name") != "d" {
+		t.Errorf("Find() = %v, want d", got)
+	}
+
+	got, err = Find(FromString(users), func(v *JSON) bool { return false })
+	if err != nil || got != nil {
+		t.Errorf("Find() = %v %v, want nil", got, err)
+	}
+}
+
+func TestFilter(t *testing.T) {
+	got, err := Filter(FromString(users), isAdmin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"a", "e"}; !reflect.DeepEqual(names(got), want) {
+		t.Errorf("Filter() = %v, want %v", names(got), want)
+	}
+}
+
+func TestMap(t *testing.T) {
+	got, err := Map(FromString(users), func(v *JSON) (interface{}, error) {
+		return v.GetInt(0, "id"), nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []interface{}{int64(1), int64(2), int64(3), int64(4), int64(5)}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Map() = %v, want %v", got, want)
+	}
+
+	// it stops at the first error of fn
+	calls := 0
+	_, err = Map(FromString(users), func(v *JSON) (interface{}, error) {
+		calls++
+		return v.ParseInt64()
+	})
+	if err == nil || calls != 1 {
+		t.Errorf("Map() error = %v after %d calls, want error after 1 call", err, calls)
+	}
+}
+
+func TestCount(t *testing.T) {
+	if got, _ := Count(FromString(users), isAdmin); got != 2 {
+		t.Errorf("Count(isAdmin) = %v, want 2", got)
+	}
+	if got, _ := Count(FromString(users), nil); got != 5 {
+		t.Errorf("Count(nil) = %v, want 5", got)
+	}
+	if got, _ := Count(FromString(`[]`), nil); got != 0 {
+		t.Errorf("Count([]) = %v, want 0", got)
+	}
+}
+
+func TestPluck(t *testing.T) {
+	got, err := Pluck(FromString(users), "age")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ages []string
+	for _, v := range got {
+		if v == nil {
+			ages = append(ages, "<nil>")
+			continue
+		}
+		ages = append(ages, v.String())
+	}
+	if want := []string{"30", "20", "<nil>", "40", "50"}; !reflect.DeepEqual(ages, want) {
+		t.Errorf("Pluck(age) = %v, want %v", ages, want)
+	}
+
+	got, err = Pluck(FromString(`[[1, 2], [3], [4, 5]]`), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 3 || got[0].String() != "2" || got[1] != nil || got[2].String() != "5" {
+		t.Errorf("Pluck(1) = %v", got)
+	}
+}
+
+func TestGroupBy(t *testing.T) {
+	got, err := GroupBy(FromString(users), "role")
+	if err != nil {
+		t.Fatal(err)
+	}
+	groups := make(map[GroupKey][]string)
+	for k, v := range got {
+		groups[k] = names(v)
+	}
+	want := map[GroupKey][]string{
+		{String, "admin"}: {"a", "e"},
+		{String, "user"}:  {"b", "c"},
+	}
+	if !reflect.DeepEqual(groups, want) {
+		t.Errorf("GroupBy(role) = %v, want %v", groups, want)
+	}
+
+	got, err = GroupBy(FromString(`[{"k": 1}, {"k": "1"}, {"k": [1]}, {"k": "[1]"}, {"k": true}, {"k": "true"}, {"k": "\u0031"}]`), "k")
+	if err != nil {
+		t.Fatal(err)
+	}
+	counts := make(map[GroupKey]int)
+	for k, v := range got {
+		counts[k] = len(v)
+	}
+	wantCounts := map[GroupKey]int{
+		{Number, "1"}:    1,
+		{String, "1"}:    2,
+		{Array, "[1]"}:   1,
+		{String, "[1]"}:  1,
+		{Bool, "true"}:   1,
+		{String, "true"}: 1,
+	}
+	if !reflect.DeepEqual(counts, wantCounts) {
+		t.Errorf("GroupBy(k) = %v, want %v", counts, wantCounts)
+	}
+}
+
+func TestCollection_Error(t *testing.T) {
+	for _, data := range []string{`{"a": 1}`, `[1, 2`, `[1,]`, ``} {
+		all := func(*JSON) bool { return true }
+		if _, err := Find(FromString(data), func(*JSON) bool { return false }); err == nil {
+			t.Errorf("Find(%s) error = nil, want error", data)
+		}
+		if _, err := Filter(FromString(data), all); err == nil {
+			t.Errorf("Filter(%s) error = nil, want error", data)
+		}
+		if _, err := Count(FromString(data), nil); err == nil {
+			t.Errorf("Count(%s) error = nil, want error", data)
+		}
+		if _, err := Pluck(FromString(data), "a"); err == nil {
+			t.Errorf("Pluck(%s) error = nil, want error", data)
+		}
+		if _, err := Map(FromString(data), func(v *JSON) (interface{}, error) { return nil, nil }); err == nil {
+			t.Errorf("Map(%s) error = nil, want error", data)
+		}
+		if _, err := GroupBy(FromString(data), "a"); err == nil {
+			t.Errorf("GroupBy(%s) error = nil, want error", data)
+		}
+	}
+}
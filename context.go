@@ -0,0 +1,105 @@
+package jzon
+
+import (
+	"context"
+	"io"
+)
+
+// contextCheckInterval is the number of steps between two checks of
+// context, it must be a power of 2
+const contextCheckInterval = 1024
+
+// SetContext sets the context which is checked periodically by scanners,
+// iterators and lookups, they stop and return ctx.Err() once ctx is done.
+// It is inherited by the iterators created from json.
+func (json *JSON) SetContext(ctx context.Context) *JSON {
+	json.ctx = ctx
+	json.ticks = 0
+	return json
+}
+
+// checkContext checks json.ctx every contextCheckInterval calls,
+// it sets json.err and returns false if the context is done
+func (json *JSON) checkContext() bool {
+	if json.ctx == nil {
+		return true
+	}
+	json.ticks++
+	if json.ticks&(contextCheckInterval-1) != 0 {
+		return true
+	}
+	if err := json.ctx.Err(); err != nil {
+		json.err = err
+		return false
+	}
+	return true
+}
+
+// withContext runs fn with ctx set to json, the previous context is
+// restored after that
+func (json *JSON) withContext(ctx context.Context, fn func() error) error {
+	if err := ctx.Err(); err != nil {
+		json.err = err
+		return err
+	}
+	prev, ticks := json.ctx, json.ticks
+	json.ctx, json.ticks = ctx, 0
+	defer func() {
+		json.ctx, json.ticks = prev, ticks
+	}()
+	return fn()
+}
+
+// CheckValidContext is like CheckValid, but it stops and returns ctx.Err()
+// once ctx is done
+func (json *JSON) CheckValidContext(ctx context.Context) error {
+	return json.withContext(ctx, json.CheckValid)
+}
+
+// PathContext is like Path, but it stops and returns ctx.Err()
+// once ctx is done
+func (json *JSON) PathContext(ctx context.Context, keys ...interface{}) error {
+	return json.withContext(ctx, func() error {
+		return json.Path(keys...)
+	})
+}
+
+// WalkContext is like Walk, but it stops and returns ctx.Err()
+// once ctx is done
+func (json *JSON) WalkContext(ctx context.Context, fn WalkFunc) error {
+	return json.withContext(ctx, func() error {
+		return json.Walk(fn)
+	})
+}
+
+// readChunkSize is the size of buffer used by FromReaderContext
+const readChunkSize = 32 * 1024
+
+// FromReaderContext is like FromReaderWithLimits, but ctx is checked
+// between reads and it is set to the returned JSON by SetContext.
+// A Read call which is blocked can not be interrupted, close the reader
+// to unblock it.
+func FromReaderContext(ctx context.Context, r io.Reader, limits Limits) (*JSON, error) {
+	buf := make([]byte, 0, readChunkSize)
+	max := limits.MaxDocumentSize
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if len(buf) == cap(buf) {
+			buf = append(buf, 0)[:len(buf)]
+		}
+		n, err := r.Read(buf[len(buf):cap(buf)])
+		buf = buf[:len(buf)+n]
+		if max > 0 && len(buf) > max {
+			return nil, LimitError{"MaxDocumentSize", max, max}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return FromBytes(buf).SetLimits(limits).SetContext(ctx), nil
+}
@@ -0,0 +1,126 @@
+package jzon
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+)
+
+// cancelAfter is a context which is canceled after Err is called n times
+type cancelAfter struct {
+	context.Context
+	n int
+}
+
+func (c *cancelAfter) Err() error {
+	if c.n <= 0 {
+		return context.Canceled
+	}
+	c.n--
+	return nil
+}
+
+func newCancelAfter(n int) *cancelAfter {
+	return &cancelAfter{context.Background(), n}
+}
+
+var bigArray = "[" + strings.Repeat(`{"a": [1, 2, {"b": "c"}]},`, 10000) + `{}]`
+
+func TestJSON_CheckValidContext(t *testing.T) {
+	json := FromString(bigArray)
+	if err := json.CheckValidContext(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.CheckValidContext(newCancelAfter(3)); err != context.Canceled {
+		t.Errorf("JSON.CheckValidContext() error = %v, want %v", err, context.Canceled)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := json.CheckValidContext(ctx); err != context.Canceled {
+		t.Errorf("JSON.CheckValidContext() with canceled context error = %v, want %v", err, context.Canceled)
+	}
+	// the context is not kept
+	if err := json.CheckValid(); err != nil {
+		t.Errorf("JSON.CheckValid() after CheckValidContext() error = %v", err)
+	}
+}
+
+func TestJSON_PathContext(t *testing.T) {
+	json := FromString(bigArray)
+	if err := json.PathContext(context.Background(), 10000); err != nil {
+		t.Fatal(err)
+	}
+	if err := FromString(bigArray).PathContext(newCancelAfter(3), 10000); err != context.Canceled {
+		t.Errorf("JSON.PathContext() error = %v, want %v", err, context.Canceled)
+	}
+	if err := FromString(`{"a": `+bigArray+`, "b": 1}`).PathContext(newCancelAfter(3), "b"); err != context.Canceled {
+		t.Errorf("JSON.PathContext() error = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestJSON_SetContext(t *testing.T) {
+	json := FromString(bigArray).SetContext(newCancelAfter(3))
+	iter, err := json.UnsafeArray()
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := 0
+	for iter.Next() {
+		n++
+	}
+	if iter.Err() != context.Canceled {
+		t.Errorf("ArrayIter.Err() = %v, want %v", iter.Err(), context.Canceled)
+	}
+	if n == 10001 {
+		t.Errorf("ArrayIter.Next() does not stop")
+	}
+
+	json = FromString(bigArray).SetContext(newCancelAfter(3))
+	if _, err := json.Interface(); err != context.Canceled {
+		t.Errorf("JSON.Interface() error = %v, want %v", err, context.Canceled)
+	}
+}
+
+// chunkReader returns data in small chunks and counts the reads
+type chunkReader struct {
+	data  string
+	reads int
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	if r.data == "" {
+		return 0, io.EOF
+	}
+	r.reads++
+	n := copy(p[:1], r.data)
+	r.data = r.data[n:]
+	return n, nil
+}
+
+func TestFromReaderContext(t *testing.T) {
+	json, err := FromReaderContext(context.Background(), &chunkReader{data: `{"a": [1, 2]}`}, Limits{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if json.String() != `{"a": [1, 2]}` {
+		t.Errorf("FromReaderContext() = %v", json)
+	}
+
+	r := &chunkReader{data: `{"a": [1, 2]}`}
+	if _, err := FromReaderContext(newCancelAfter(3), r, Limits{}); err != context.Canceled {
+		t.Errorf("FromReaderContext() error = %v, want %v", err, context.Canceled)
+	}
+	if r.reads != 3 {
+		t.Errorf("FromReaderContext() reads %d times after canceled, want 3", r.reads)
+	}
+
+	_, err = FromReaderContext(context.Background(), strings.NewReader(bigArray), Limits{MaxDocumentSize: 100})
+	if _, ok := err.(LimitError); !ok {
+		t.Errorf("FromReaderContext() error = %v, want LimitError", err)
+	}
+	json, err = FromReaderContext(context.Background(), strings.NewReader(bigArray), Limits{})
+	if err != nil || json.String() != bigArray {
+		t.Errorf("FromReaderContext() of big array = %v", err)
+	}
+}
@@ -0,0 +1,79 @@
+package jzon
+
+// DuplicateKeyPolicy defines how to deal with duplicate keys in an object
+type DuplicateKeyPolicy uint
+
+const (
+	// DuplicateKeyAllow is the default policy, lookup returns the first
+	// matched key and iteration yields every member
+	DuplicateKeyAllow DuplicateKeyPolicy = iota
+	// DuplicateKeyReject treats duplicate keys as an error,
+	// a DuplicateKeyError is returned
+	DuplicateKeyReject
+	// DuplicateKeyFirstWins keeps the first member and ignores the others
+	DuplicateKeyFirstWins
+	// DuplicateKeyLastWins keeps the last member and ignores the others
+	DuplicateKeyLastWins
+)
+
+func (p DuplicateKeyPolicy) String() string {
+	switch p {
+	case DuplicateKeyAllow:
+		return "Allow"
+	case DuplicateKeyReject:
+		return "Reject"
+	case DuplicateKeyFirstWins:
+		return "FirstWins"
+	case DuplicateKeyLastWins:
+		return "LastWins"
+	default:
+		return "Unkown"
+	}
+}
+
+// SetDuplicateKeyPolicy sets the policy applied to duplicate object keys.
+// It is honored by ObjectIndex, Path, Object, UnsafeObject and CheckValid,
+// and inherited by the iterators created from json.
+func (json *JSON) SetDuplicateKeyPolicy(p DuplicateKeyPolicy) *JSON {
+	json.dupPolicy = p
+	return json
+}
+
+// duplicateKeys scans the members of the object represented by
+// json.data[head:tail] and applies the duplicate key policy.
+// It returns the offsets (relative to json.head) of keys which
+// should be skipped by ObjectIter.
+func (json *JSON) duplicateKeys() (map[int]bool, error) {
+	iter := &ObjectIter{
+		JSON: FromBytes(json.data[json.head:json.tail]),
+	}
+
+	seen := make(map[string]int)
+	skip := make(map[int]bool)
+	for iter.Next() {
+		key := iter.Key()
+		offset := iter.keyOffset
+		first, ok := seen[key]
+		if !ok {
+			seen[key] = offset
+			continue
+		}
+		switch json.dupPolicy {
+		case DuplicateKeyReject:
+			return nil, DuplicateKeyError{
+				Key:    key,
+				First:  json.head + first,
+				Second: json.head + offset,
+			}
+		case DuplicateKeyFirstWins:
+			skip[offset] = true
+		case DuplicateKeyLastWins:
+			skip[first] = true
+			seen[key] = offset
+		}
+	}
+	if iter.err != nil {
+		return nil, iter.err
+	}
+	return skip, nil
+}
@@ -0,0 +1,147 @@
+package jzon
+
+import (
+	"math"
+	"math/big"
+	"math/bits"
+	"sync"
+)
+
+// This file implements the Eisel-Lemire algorithm to convert decimal
+// to float64 without allocation, it is ported from the strconv package
+// of Go 1.16. See https://nigeltao.github.io/blog/2020/eisel-lemire.html
+//
+// Copyright 2020 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+const (
+	detailedPowersOfTenMinExp10 = -348
+	detailedPowersOfTenMaxExp10 = +347
+)
+
+var (
+	detailedPowersOfTenOnce sync.Once
+	// detailedPowersOfTen contains 128-bit mantissa approximations
+	// (rounded down) to the powers of 10, {low 64 bits, high 64 bits}.
+	// The mantissas are normalized (highest bit set).
+	detailedPowersOfTen [detailedPowersOfTenMaxExp10 - detailedPowersOfTenMinExp10 + 1][2]uint64
+)
+
+// buildDetailedPowersOfTen computes detailedPowersOfTen by math/big
+// instead of embedding the 700 lines table
+func buildDetailedPowersOfTen() {
+	ten := big.NewInt(10)
+	mask := new(big.Int).SetUint64(math.MaxUint64)
+	for e := detailedPowersOfTenMinExp10; e <= detailedPowersOfTenMaxExp10; e++ {
+		m := new(big.Int)
+		if e >= 0 {
+			m.Exp(ten, big.NewInt(int64(e)), nil)
+			if n := m.BitLen(); n > 128 {
+				m.Rsh(m, uint(n-128))
+			} else {
+				m.Lsh(m, uint(128-n))
+			}
+		} else {
+			// floor(2^(127+n) / 10^-e) is in [2^127, 2^128)
+			d := new(big.Int).Exp(ten, big.NewInt(int64(-e)), nil)
+			m.Lsh(big.NewInt(1), uint(127+d.BitLen()))
+			m.Quo(m, d)
+		}
+		lo := new(big.Int).And(m, mask).Uint64()
+		hi := new(big.Int).Rsh(m, 64).Uint64()
+		detailedPowersOfTen[e-detailedPowersOfTenMinExp10] = [2]uint64{lo, hi}
+	}
+}
+
+// float64pow10 are the exact powers of 10 in float64
+var float64pow10 = [...]float64{
+	1e0, 1e1, 1e2, 1e3, 1e4, 1e5, 1e6, 1e7, 1e8, 1e9,
+	1e10, 1e11, 1e12, 1e13, 1e14, 1e15, 1e16, 1e17, 1e18, 1e19,
+	1e20, 1e21, 1e22,
+}
+
+// exactFloat64 converts man * 10^exp10 to float64 if both of them can be
+// represented exactly, the result is exact too (Clinger's fast path)
+func exactFloat64(man uint64, exp10 int, neg bool) (f float64, ok bool) {
+	if man>>53 != 0 || exp10 < -22 || exp10 > 22 {
+		return 0, false
+	}
+	f = float64(man)
+	if exp10 < 0 {
+		f /= float64pow10[-exp10]
+	} else {
+		f *= float64pow10[exp10]
+	}
+	if neg {
+		f = -f
+	}
+	return f, true
+}
+
+func eiselLemire64(man uint64, exp10 int, neg bool) (f float64, ok bool) {
+	// Exp10 Range.
+	if man == 0 {
+		if neg {
+			f = math.Float64frombits(0x8000000000000000) // Negative zero.
+		}
+		return f, true
+	}
+	if exp10 < detailedPowersOfTenMinExp10 || detailedPowersOfTenMaxExp10 < exp10 {
+		return 0, false
+	}
+	detailedPowersOfTenOnce.Do(buildDetailedPowersOfTen)
+
+	// Normalization.
+	clz := bits.LeadingZeros64(man)
+	man <<= uint(clz)
+	const float64ExponentBias = 1023
+	retExp2 := uint64(217706*exp10>>16+64+float64ExponentBias) - uint64(clz)
+
+	// Multiplication.
+	xHi, xLo := bits.Mul64(man, detailedPowersOfTen[exp10-detailedPowersOfTenMinExp10][1])
+
+	// Wider Approximation.
+	if xHi&0x1FF == 0x1FF && xLo+man < man {
+		yHi, yLo := bits.Mul64(man, detailedPowersOfTen[exp10-detailedPowersOfTenMinExp10][0])
+		mergedHi, mergedLo := xHi, xLo+yHi
+		if mergedLo < xLo {
+			mergedHi++
+		}
+		if mergedHi&0x1FF == 0x1FF && mergedLo+1 == 0 && yLo+man < man {
+			return 0, false
+		}
+		xHi, xLo = mergedHi, mergedLo
+	}
+
+	// Shifting to 54 Bits.
+	msb := xHi >> 63
+	retMantissa := xHi >> (msb + 9)
+	retExp2 -= 1 ^ msb
+
+	// Half-way Ambiguity.
+	if xLo == 0 && xHi&0x1FF == 0 && retMantissa&3 == 1 {
+		return 0, false
+	}
+
+	// From 54 to 53 Bits.
+	retMantissa += retMantissa & 1
+	retMantissa >>= 1
+	if retMantissa>>53 > 0 {
+		retMantissa >>= 1
+		retExp2++
+	}
+	// retExp2 is a uint64. Zero or underflow means that we're in subnormal
+	// float64 space. 0x7FF or above means that we're in Inf/NaN float64 space.
+	//
+	// The if block is equivalent to (but has fewer branches than):
+	//   if retExp2 <= 0 || retExp2 >= 0x7FF { etc }
+	if retExp2-1 >= 0x7FF-1 {
+		return 0, false
+	}
+	retBits := retExp2<<52 | retMantissa&0x000FFFFFFFFFFFFF
+	if neg {
+		retBits |= 0x8000000000000000
+	}
+	return math.Float64frombits(retBits), true
+}
@@ -0,0 +1,123 @@
+package jzon
+
+import (
+	"math"
+	"math/rand"
+	"strconv"
+	"testing"
+)
+
+func TestDetailedPowersOfTen(t *testing.T) {
+	detailedPowersOfTenOnce.Do(buildDetailedPowersOfTen)
+	tests := []struct {
+		exp10 int
+		want  [2]uint64
+	}{
+		{-348, [2]uint64{0x1732C869CD60E453, 0xFA8FD5A0081C0288}},
+		{-1, [2]uint64{0xCCCCCCCCCCCCCCCC, 0xCCCCCCCCCCCCCCCC}},
+		{0, [2]uint64{0x0000000000000000, 0x8000000000000000}},
+		{1, [2]uint64{0x0000000000000000, 0xA000000000000000}},
+		{347, [2]uint64{0x4B7195F2D2D1A9FB, 0xD13EB46469447567}},
+	}
+	for _, tt := range tests {
+		got := detailedPowersOfTen[tt.exp10-detailedPowersOfTenMinExp10]
+		if got != tt.want {
+			t.Errorf("detailedPowersOfTen[1e%d] = %#x, want %#x", tt.exp10, got, tt.want)
+		}
+	}
+}
+
+func TestParseFloatBytes(t *testing.T) {
+	tests := []string{
+		"0", "-0", "1", "-1", "0.1", "1.5", "123.456e7", "1e22", "1e23", "-1e-22",
+		"4.9406564584124654e-324", "2.2250738585072011e-308", "2.2250738585072014e-308",
+		"1.7976931348623157e308", "1.7976931348623159e308", "1e400", "-1e400", "1e-400",
+		"9007199254740993", "9007199254740992.5", "123456789012345678901234567890",
+		"0.000000000000000000000000000001", "3.14159265358979323846264338327950288",
+		"7.038531e-26", "2.47032822920623272e-324", "1.00000000000000011102230246251565404236316680908203125",
+		"1.00000000000000011102230246251565404236316680908203124", "100000000000000016777215",
+		"100000000000000016777216", "18446744073709551615", "18446744073709551616",
+	}
+	for _, s := range tests {
+		checkParseFloatBytes(t, s)
+	}
+
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 100000; i++ {
+		var s string
+		switch i % 3 {
+		case 0:
+			s = strconv.FormatFloat(math.Float64frombits(r.Uint64()), 'g', -1, 64)
+		case 1:
+			s = strconv.FormatFloat(r.NormFloat64()*math.Pow(10, float64(r.Intn(40)-20)), 'e', r.Intn(25), 64)
+		default:
+			s = strconv.FormatUint(r.Uint64(), 10) + "." + strconv.FormatUint(r.Uint64(), 10) + "e" + strconv.Itoa(r.Intn(600)-300)
+		}
+		if s == "NaN" || s == "+Inf" || s == "-Inf" {
+			continue
+		}
+		checkParseFloatBytes(t, s)
+	}
+}
+
+func checkParseFloatBytes(t *testing.T, s string) {
+	t.Helper()
+	json := FromString(s)
+	b, f, err := json.number("test", "float")
+	if err != nil {
+		t.Fatalf("JSON.number(%q) error = %v", s, err)
+	}
+	got, gotErr := parseFloatBytes(b, f)
+	want, wantErr := strconv.ParseFloat(s, 64)
+	if math.Float64bits(got) != math.Float64bits(want) || (gotErr != nil) != (wantErr != nil) {
+		t.Errorf("parseFloatBytes(%q) = %v, %v, want %v, %v", s, got, gotErr, want, wantErr)
+	}
+}
+
+func TestJSON_ParseNumber_Allocs(t *testing.T) {
+	ints := FromString(`-1234567890123`)
+	floats := FromString(`-12345.678901e-12`)
+	allocs := testing.AllocsPerRun(100, func() {
+		if _, err := ints.ParseInt64(); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := floats.ParseFloat(); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("JSON.ParseInt64() and JSON.ParseFloat() allocs = %v, want 0", allocs)
+	}
+}
+
+func BenchmarkJSON_ParseInt64(b *testing.B) {
+	json := FromString(`-1234567890123`)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		json.ParseInt64()
+	}
+}
+
+func BenchmarkStrconv_ParseInt(b *testing.B) {
+	data := []byte(`-1234567890123`)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		strconv.ParseInt(string(data), 10, 64)
+	}
+}
+
+func BenchmarkJSON_ParseFloat(b *testing.B) {
+	json := FromString(`-12345.678901e-12`)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		json.ParseFloat()
+	}
+}
+
+func BenchmarkStrconv_ParseFloat(b *testing.B) {
+	data := []byte(`-12345.678901e-12`)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		strconv.ParseFloat(string(data), 64)
+	}
+}
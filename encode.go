@@ -0,0 +1,202 @@
+package jzon
+
+import (
+	"bytes"
+	"encoding/base64"
+	stdjson "encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+)
+
+// Marshal returns the JSON encoding of v.
+//
+// *JSON is written as it is without re-encoding, *OrderedObject and
+// OrderedMap keep the order of keys, map[string]interface{} is sorted by
+// keys. Go values of basic types are encoded like encoding/json except
+// that HTML characters are not escaped, and json.Marshaler is honored.
+// Other types are delegated to encoding/json.
+func Marshal(v interface{}) ([]byte, error) {
+	return AppendMarshal(nil, v)
+}
+
+// MarshalIndent is like Marshal but applies Indent to format the output
+func MarshalIndent(v interface{}, prefix, indent string) ([]byte, error) {
+	b, err := Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := stdjson.Indent(&buf, b, prefix, indent); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// AppendMarshal appends the JSON encoding of v to dst
+func AppendMarshal(dst []byte, v interface{}) ([]byte, error) {
+	switch v := v.(type) {
+	case nil:
+		return append(dst, nullBytes...), nil
+	case *JSON:
+		if v == nil {
+			return append(dst, nullBytes...), nil
+		}
+		return v.appendRaw(dst)
+	case *OrderedObject:
+		if v == nil {
+			return append(dst, nullBytes...), nil
+		}
+		return v.appendJSON(dst)
+	case OrderedMap:
+		if v == nil {
+			return append(dst, nullBytes...), nil
+		}
+		dst = append(dst, '{')
+		for i, item := range v {
+			if i > 0 {
+				dst = append(dst, ',')
+			}
+			dst = appendQuote(dst, item.Key)
+			dst = append(dst, ':')
+			var err error
+			if dst, err = AppendMarshal(dst, item.Value); err != nil {
+				return dst, err
+			}
+		}
+		return append(dst, '}'), nil
+	case map[string]interface{}:
+		if v == nil {
+			return append(dst, nullBytes...), nil
+		}
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		dst = append(dst, '{')
+		for i, k := range keys {
+			if i > 0 {
+				dst = append(dst, ',')
+			}
+			dst = appendQuote(dst, k)
+			dst = append(dst, ':')
+			var err error
+			if dst, err = AppendMarshal(dst, v[k]); err != nil {
+				return dst, err
+			}
+		}
+		return append(dst, '}'), nil
+	case []interface{}:
+		if v == nil {
+			return append(dst, nullBytes...), nil
+		}
+		dst = append(dst, '[')
+		for i, e := range v {
+			if i > 0 {
+				dst = append(dst, ',')
+			}
+			var err error
+			if dst, err = AppendMarshal(dst, e); err != nil {
+				return dst, err
+			}
+		}
+		return append(dst, ']'), nil
+	case string:
+		return appendQuote(dst, v), nil
+	case bool:
+		return strconv.AppendBool(dst, v), nil
+	case int:
+		return strconv.AppendInt(dst, int64(v), 10), nil
+	case int8:
+		return strconv.AppendInt(dst, int64(v), 10), nil
+	case int16:
+		return strconv.AppendInt(dst, int64(v), 10), nil
+	case int32:
+		return strconv.AppendInt(dst, int64(v), 10), nil
+	case int64:
+		return strconv.AppendInt(dst, v, 10), nil
+	case uint:
+		return strconv.AppendUint(dst, uint64(v), 10), nil
+	case uint8:
+		return strconv.AppendUint(dst, uint64(v), 10), nil
+	case uint16:
+		return strconv.AppendUint(dst, uint64(v), 10), nil
+	case uint32:
+		return strconv.AppendUint(dst, uint64(v), 10), nil
+	case uint64:
+		return strconv.AppendUint(dst, v, 10), nil
+	case float32:
+		return appendFloat(dst, float64(v), 32)
+	case float64:
+		return appendFloat(dst, v, 64)
+	case stdjson.Number:
+		if v == "" {
+			v = "0"
+		}
+		if !isValidNumber(string(v)) {
+			return dst, fmt.Errorf("jzon: invalid number literal %q", v)
+		}
+		return append(dst, v...), nil
+	case []byte:
+		if v == nil {
+			return append(dst, nullBytes...), nil
+		}
+		dst = append(dst, '"')
+		n := len(dst)
+		dst = append(dst, make([]byte, base64.StdEncoding.EncodedLen(len(v)))...)
+		base64.StdEncoding.Encode(dst[n:], v)
+		return append(dst, '"'), nil
+	}
+
+	b, err := stdjson.Marshal(v)
+	if err != nil {
+		return dst, err
+	}
+	return append(dst, b...), nil
+}
+
+// appendRaw appends the json value as it is, the whitespaces
+// around value are trimmed. It is checked strictly first, like
+// encoding/json does for the output of Marshaler.
+func (json *JSON) appendRaw(dst []byte) ([]byte, error) {
+	raw := json.RawMessage()
+	if len(raw) == 0 {
+		return dst, fmt.Errorf("jzon: can not marshal empty JSON")
+	}
+	if _, err := json.validView(); err != nil {
+		return dst, err
+	}
+	return append(dst, raw...), nil
+}
+
+// appendFloat appends f like encoding/json does
+func appendFloat(dst []byte, f float64, bits int) ([]byte, error) {
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		return dst, fmt.Errorf("jzon: unsupported float value %s", strconv.FormatFloat(f, 'g', -1, bits))
+	}
+	format := byte('f')
+	if abs := math.Abs(f); abs != 0 {
+		if bits == 64 && (abs < 1e-6 || abs >= 1e21) || bits == 32 && (float32(abs) < 1e-6 || float32(abs) >= 1e21) {
+			format = 'e'
+		}
+	}
+	n := len(dst)
+	dst = strconv.AppendFloat(dst, f, format, -1, bits)
+	if format == 'e' {
+		// clean up e-09 to e-9
+		if m := len(dst) - n; m >= 4 && dst[len(dst)-4] == 'e' && dst[len(dst)-3] == '-' && dst[len(dst)-2] == '0' {
+			dst[len(dst)-2] = dst[len(dst)-1]
+			dst = dst[:len(dst)-1]
+		}
+	}
+	return dst, nil
+}
+
+// isValidNumber reports whether s is a valid JSON number literal
+func isValidNumber(s string) bool {
+	json := FromString(s)
+	end := json.validNumberEnd()
+	return end == len(s)
+}
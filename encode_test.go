@@ -0,0 +1,84 @@
+package jzon
+
+import (
+	stdjson "encoding/json"
+	"math"
+	"testing"
+)
+
+func TestMarshal(t *testing.T) {
+	tests := []struct {
+		name    string
+		v       interface{}
+		want    string
+		wantErr bool
+	}{
+		{"1", nil, `null`, false},
+		{"2", true, `true`, false},
+		{"3", "a\"b\\c\n\x01<>&\u2028\xff", `"a\"b\\c\n\u0001<>&\u2028\ufffd"`, false},
+		{"4", -42, `-42`, false},
+		{"5", uint8(255), `255`, false},
+		{"6", 1.5, `1.5`, false},
+		{"7", 1e21, `1e+21`, false},
+		{"8", 1e-7, `1e-7`, false},
+		{"9", float32(0.1), `0.1`, false},
+		{"10", math.NaN(), ``, true},
+		{"11", stdjson.Number("1.50e3"), `1.50e3`, false},
+		{"12", stdjson.Number("abc"), ``, true},
+		{"13", []byte("hello"), `"aGVsbG8="`, false},
+		{"14", map[string]interface{}{"b": 1, "a": []interface{}{nil, "x"}}, `{"a":[null,"x"],"b":1}`, false},
+		{"15", OrderedMap{{"b", 1}, {"a", OrderedMap{}}}, `{"b":1,"a":{}}`, false},
+		{"16", FromString(` {"a": [1, 2]} `), `{"a": [1, 2]}`, false},
+		{"17", []interface{}{FromString(`1`), (*JSON)(nil)}, `[1,null]`, false},
+		{"18", struct {
+			A int `json:"a"`
+		}{1}, `{"a":1}`, false},
+		{"19", FromString(``), ``, true},
+		{"20", FromString(`{"a":1} junk`), ``, true},
+		{"21", FromString(`[1]]`), ``, true},
+		{"22", FromString(`1 2`), ``, true},
+		{"23", []interface{}{FromString(`{bad`)}, ``, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Marshal(tt.v)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Marshal() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if string(got) != tt.want {
+				t.Errorf("Marshal() = %s, want %s", got, tt.want)
+			}
+			if !stdjson.Valid(got) {
+				t.Errorf("Marshal() = %s is invalid", got)
+			}
+		})
+	}
+}
+
+func TestMarshal_Float(t *testing.T) {
+	for _, f := range []float64{0, -0.0, 1, 100, 1e20, 1e21, 1e-6, 1e-7, 123456789.123, -5e-324, math.MaxFloat64} {
+		got, err := Marshal(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, _ := stdjson.Marshal(f)
+		if string(got) != string(want) {
+			t.Errorf("Marshal(%v) = %s, want %s", f, got, want)
+		}
+	}
+}
+
+func TestMarshalIndent(t *testing.T) {
+	got, err := MarshalIndent(OrderedMap{{"b", 1}, {"a", []interface{}{true}}}, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "{\n  \"b\": 1,\n  \"a\": [\n    true\n  ]\n}"
+	if string(got) != want {
+		t.Errorf("MarshalIndent() = %s, want %s", got, want)
+	}
+}
@@ -18,6 +18,9 @@ func (e SyntaxError) Error() string {
 	if end > len(e.data) {
 		end = len(e.data)
 	}
+	if start > end {
+		start = end
+	}
 
 	return fmt.Sprintf("JSON syntax error when parsing kind(%s), index %d, context near: |%s|", e.kind, e.offset, string(e.data[start:end]))
 }
@@ -36,3 +39,38 @@ func (e *KindError) Error() string {
 	}
 	return "jzon: call of " + e.Method + " on " + e.Kind.String() + " JSON"
 }
+
+// A DuplicateKeyError occurs when an object contains the same key more than
+// once and the DuplicateKeyReject policy is used.
+// First and Second are the indexes of the two keys.
+type DuplicateKeyError struct {
+	Key    string
+	First  int
+	Second int
+}
+
+func (e DuplicateKeyError) Error() string {
+	return fmt.Sprintf("jzon: duplicate key %q at index %d, first defined at index %d", e.Key, e.Second, e.First)
+}
+
+// A LimitError occurs when the JSON exceeds one of the Limits.
+// Limit is the name of field in Limits, Offset is the index where
+// the limit is exceeded.
+type LimitError struct {
+	Limit  string
+	Max    int
+	Offset int
+}
+
+func (e LimitError) Error() string {
+	return fmt.Sprintf("jzon: exceeded %s limit %d, index %d", e.Limit, e.Max, e.Offset)
+}
+
+// A MediaTypeError occurs when the Content-Type of request is not JSON
+type MediaTypeError struct {
+	MediaType string
+}
+
+func (e MediaTypeError) Error() string {
+	return fmt.Sprintf("jzon: unsupported media type %q", e.MediaType)
+}
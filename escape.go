@@ -37,6 +37,26 @@ func unquoteBytes(s []byte) (t []byte, ok bool) {
 	// Check for unusual characters. If there are none,
 	// then no unquoting is needed, so return a slice of the
 	// original bytes.
+	r := plainPrefix(s)
+	if r == len(s) {
+		return s, true
+	}
+
+	b := make([]byte, r, len(s)+2*utf8.UTFMax)
+	copy(b, s[0:r])
+	return appendUnquoted(b, s[r:])
+}
+
+// appendUnquote appends the unquoted quoted JSON string literal s to dst
+func appendUnquote(dst, s []byte) ([]byte, bool) {
+	if len(s) < 2 || s[0] != '"' || s[len(s)-1] != '"' {
+		return dst, false
+	}
+	return appendUnquoted(dst, s[1:len(s)-1])
+}
+
+// plainPrefix returns the length of prefix of s which needs no unquoting
+func plainPrefix(s []byte) int {
 	r := 0
 	for r < len(s) {
 		c := s[r]
@@ -53,91 +73,190 @@ func unquoteBytes(s []byte) (t []byte, ok bool) {
 		}
 		r += size
 	}
-	if r == len(s) {
-		return s, true
-	}
+	return r
+}
 
-	b := make([]byte, len(s)+2*utf8.UTFMax)
-	w := copy(b, s[0:r])
-	for r < len(s) {
-		// Out of room?  Can only happen if s is full of
-		// malformed UTF-8 and we're replacing each
-		// byte with RuneError.
-		if w >= len(b)-2*utf8.UTFMax {
-			nb := make([]byte, (len(b)+utf8.UTFMax)*2)
-			copy(nb, b[0:w])
-			b = nb
+// appendUnquoted appends the unquoted content s of JSON string literal to dst
+func appendUnquoted(dst, s []byte) ([]byte, bool) {
+	var buf [utf8.UTFMax]byte
+	for r := 0; r < len(s); {
+		if c := s[r]; c >= ' ' && c < utf8.RuneSelf && c != '\\' && c != '"' {
+			// ASCII
+			dst = append(dst, c)
+			r++
+			continue
 		}
-		switch c := s[r]; {
-		case c == '\\':
+		rr, size, ok := unquoteRune(s, r)
+		if !ok {
+			return dst, false
+		}
+		r += size
+		n := utf8.EncodeRune(buf[:], rr)
+		dst = append(dst, buf[:n]...)
+	}
+	return dst, true
+}
+
+// unquoteEqual reports whether the quoted JSON string literal q
+// is equal to s after unquoting, it does not allocate
+func unquoteEqual(q []byte, s string) bool {
+	if len(q) < 2 || q[0] != '"' || q[len(q)-1] != '"' {
+		return false
+	}
+	q = q[1 : len(q)-1]
+	if r := plainPrefix(q); r == len(q) {
+		// the compiler does not allocate for this conversion
+		return string(q) == s
+	}
+
+	var buf [utf8.UTFMax]byte
+	j := 0
+	for r := 0; r < len(q); {
+		if c := q[r]; c >= ' ' && c < utf8.RuneSelf && c != '\\' && c != '"' {
+			// ASCII
+			if j >= len(s) || s[j] != c {
+				return false
+			}
 			r++
-			if r >= len(s) {
-				return
+			j++
+			continue
+		}
+		rr, size, ok := unquoteRune(q, r)
+		if !ok {
+			return false
+		}
+		r += size
+		n := utf8.EncodeRune(buf[:], rr)
+		if j+n > len(s) || string(buf[:n]) != s[j:j+n] {
+			return false
+		}
+		j += n
+	}
+	return j == len(s)
+}
+
+// unquoteRune decodes the rune at s[r], which may be an escape sequence,
+// a multi-byte character or an ASCII character. Malformed UTF-8 and
+// invalid surrogate are replaced by RuneError.
+// It returns the rune and the number of bytes consumed.
+func unquoteRune(s []byte, r int) (rune, int, bool) {
+	switch c := s[r]; {
+	case c == '\\':
+		if r+1 >= len(s) {
+			return 0, 0, false
+		}
+		switch s[r+1] {
+		default:
+			return 0, 0, false
+		case '"', '\\', '/', '\'':
+			return rune(s[r+1]), 2, true
+		case 'b':
+			return '\b', 2, true
+		case 'f':
+			return '\f', 2, true
+		case 'n':
+			return '\n', 2, true
+		case 'r':
+			return '\r', 2, true
+		case 't':
+			return '\t', 2, true
+		case 'u':
+			rr := getu4(s[r:])
+			if rr < 0 {
+				return 0, 0, false
 			}
-			switch s[r] {
-			default:
-				return
-			case '"', '\\', '/', '\'':
-				b[w] = s[r]
-				r++
-				w++
-			case 'b':
-				b[w] = '\b'
-				r++
-				w++
-			case 'f':
-				b[w] = '\f'
-				r++
-				w++
-			case 'n':
-				b[w] = '\n'
-				r++
-				w++
-			case 'r':
-				b[w] = '\r'
-				r++
-				w++
-			case 't':
-				b[w] = '\t'
-				r++
-				w++
-			case 'u':
-				r--
-				rr := getu4(s[r:])
-				if rr < 0 {
-					return
+			if utf16.IsSurrogate(rr) {
+				rr1 := getu4(s[r+6:])
+				if dec := utf16.DecodeRune(rr, rr1); dec != unicode.ReplacementChar {
+					// A valid pair; consume.
+					return dec, 12, true
 				}
-				r += 6
-				if utf16.IsSurrogate(rr) {
-					rr1 := getu4(s[r:])
-					if dec := utf16.DecodeRune(rr, rr1); dec != unicode.ReplacementChar {
-						// A valid pair; consume.
-						r += 6
-						w += utf8.EncodeRune(b[w:], dec)
-						break
-					}
-					// Invalid surrogate; fall back to replacement rune.
-					rr = unicode.ReplacementChar
-				}
-				w += utf8.EncodeRune(b[w:], rr)
+				// Invalid surrogate; fall back to replacement rune.
+				rr = unicode.ReplacementChar
 			}
+			return rr, 6, true
+		}
 
-		// Quote, control characters are invalid.
-		case c == '"', c < ' ':
-			return
+	// Quote, control characters are invalid.
+	case c == '"', c < ' ':
+		return 0, 0, false
 
-		// ASCII
-		case c < utf8.RuneSelf:
-			b[w] = c
-			r++
-			w++
+	// ASCII
+	case c < utf8.RuneSelf:
+		return rune(c), 1, true
 
-		// Coerce to well-formed UTF-8.
-		default:
-			rr, size := utf8.DecodeRune(s[r:])
-			r += size
-			w += utf8.EncodeRune(b[w:], rr)
+	// Coerce to well-formed UTF-8.
+	default:
+		rr, size := utf8.DecodeRune(s[r:])
+		return rr, size, true
+	}
+}
+
+// unquoteInto unquotes the quoted JSON string literal q, it returns the
+// slice of q if no unquoting is needed, otherwise the result is decoded
+// into buf[:0]
+func unquoteInto(buf, q []byte) ([]byte, bool) {
+	if len(q) < 2 || q[0] != '"' || q[len(q)-1] != '"' {
+		return nil, false
+	}
+	q = q[1 : len(q)-1]
+	r := plainPrefix(q)
+	if r == len(q) {
+		return q, true
+	}
+	buf = append(buf[:0], q[:r]...)
+	return appendUnquoted(buf, q[r:])
+}
+
+const hex = "0123456789abcdef"
+
+// appendQuote appends s as a quoted JSON string literal to dst. Quote,
+// backslash, control characters, U+2028 and U+2029 are escaped, invalid
+// UTF-8 is replaced by RuneError like encoding/json. HTML characters are
+// not escaped.
+func appendQuote(dst []byte, s string) []byte {
+	dst = append(dst, '"')
+	start := 0
+	for i := 0; i < len(s); {
+		if c := s[i]; c < utf8.RuneSelf {
+			if c >= ' ' && c != '"' && c != '\\' {
+				i++
+				continue
+			}
+			dst = append(dst, s[start:i]...)
+			switch c {
+			case '"', '\\':
+				dst = append(dst, '\\', c)
+			case '\n':
+				dst = append(dst, '\\', 'n')
+			case '\r':
+				dst = append(dst, '\\', 'r')
+			case '\t':
+				dst = append(dst, '\\', 't')
+			default:
+				dst = append(dst, '\\', 'u', '0', '0', hex[c>>4], hex[c&0xf])
+			}
+			i++
+			start = i
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			dst = append(dst, s[start:i]...)
+			dst = append(dst, `\ufffd`...)
+			i += size
+			start = i
+			continue
+		}
+		if r == '\u2028' || r == '\u2029' {
+			dst = append(dst, s[start:i]...)
+			dst = append(dst, '\\', 'u', '2', '0', '2', hex[r&0xf])
+			i += size
+			start = i
+			continue
 		}
+		i += size
 	}
-	return b[0:w], true
+	dst = append(dst, s[start:]...)
+	return append(dst, '"')
 }
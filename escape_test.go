@@ -0,0 +1,54 @@
+package jzon
+
+import "testing"
+
+var quotedStrings = []string{
+	`""`, `"abc"`, `"a\tb"`, `"\"quoted\""`, `"\\\/\b\f\n\r\t"`, `"été"`,
+	`"中文"`, `"😀"`, `"\ud83d"`, `"\ud83dx"`, "\"\xff\xfe\"", `"\x"`, `"\u12"`,
+	`"unterminated`, `"a"b"`,
+}
+
+func Test_unquoteEqual(t *testing.T) {
+	candidates := []string{"", "abc", "a\tb", `"quoted"`, "\\/\b\f\n\r\t", "été", "中文", "😀", "�", "�x", "��", "a", "ab"}
+	for _, q := range quotedStrings {
+		want, ok := unquote([]byte(q))
+		for _, s := range candidates {
+			if got := unquoteEqual([]byte(q), s); got != (ok && want == s) {
+				t.Errorf("unquoteEqual(%q, %q) = %v, want %v", q, s, got, ok && want == s)
+			}
+		}
+		if ok {
+			if got := unquoteEqual([]byte(q), want); !got {
+				t.Errorf("unquoteEqual(%q, %q) = false, want true", q, want)
+			}
+		}
+	}
+}
+
+func Test_unquoteInto(t *testing.T) {
+	buf := make([]byte, 0, 64)
+	for _, q := range quotedStrings {
+		want, wantOK := unquote([]byte(q))
+		got, ok := unquoteInto(buf, []byte(q))
+		if ok != wantOK || (ok && string(got) != want) {
+			t.Errorf("unquoteInto(%q) = %q, %v, want %q, %v", q, got, ok, want, wantOK)
+		}
+		got, ok = appendUnquote([]byte("prefix"), []byte(q))
+		if ok != wantOK || (ok && string(got) != "prefix"+want) {
+			t.Errorf("appendUnquote(%q) = %q, %v, want %q, %v", q, got, ok, "prefix"+want, wantOK)
+		}
+	}
+}
+
+func Test_unquoteEqual_Allocs(t *testing.T) {
+	q := []byte(`"kéy\twith\nescapes and a long tail to be compared"`)
+	s := "kéy\twith\nescapes and a long tail to be compared"
+	allocs := testing.AllocsPerRun(100, func() {
+		if !unquoteEqual(q, s) {
+			t.Fatal("unquoteEqual() = false, want true")
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("unquoteEqual() allocs = %v, want 0", allocs)
+	}
+}
@@ -0,0 +1,93 @@
+package jzon
+
+import (
+	"errors"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// DefaultMaxRequestSize is the size limit of request body in ReadRequest
+// if limits.MaxDocumentSize is not set
+const DefaultMaxRequestSize = 10 << 20
+
+// ReadRequest reads the body of r as JSON, it is checked strictly with
+// limits and only whitespaces are allowed after the JSON value.
+// A MediaTypeError is returned if the Content-Type is not
+// application/json or */*+json. The body is read by http.MaxBytesReader,
+// a LimitError is returned once it exceeds limits.MaxDocumentSize, or
+// the *http.MaxBytesError if it exceeds DefaultMaxRequestSize and
+// MaxDocumentSize is not set. Use StatusCode to map the error to HTTP
+// status.
+func ReadRequest(r *http.Request, limits Limits) (*JSON, error) {
+	ct := r.Header.Get("Content-Type")
+	mediaType, _, err := mime.ParseMediaType(ct)
+	if err != nil || !isJSONMediaType(mediaType) {
+		return nil, MediaTypeError{ct}
+	}
+	if r.Body == nil {
+		return nil, SyntaxError{Invalid, 0, nil}
+	}
+
+	max := int64(DefaultMaxRequestSize)
+	if limits.MaxDocumentSize > 0 {
+		max = int64(limits.MaxDocumentSize)
+	}
+	json, err := FromReaderWithLimits(http.MaxBytesReader(nil, r.Body, max), limits)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) && limits.MaxDocumentSize > 0 {
+			return nil, LimitError{"MaxDocumentSize", limits.MaxDocumentSize, limits.MaxDocumentSize}
+		}
+		return nil, err
+	}
+	if err := json.CheckComplete(); err != nil {
+		return nil, err
+	}
+	return json, nil
+}
+
+func isJSONMediaType(mediaType string) bool {
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// WriteResponse encodes v by Marshal and writes it to w with status,
+// nothing is written if v can not be encoded
+func WriteResponse(w http.ResponseWriter, status int, v interface{}) error {
+	b, err := Marshal(v)
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_, err = w.Write(b)
+	return err
+}
+
+// WriteError writes err as {"error": "message"} with the status
+// returned by StatusCode
+func WriteError(w http.ResponseWriter, err error) error {
+	return WriteResponse(w, StatusCode(err), OrderedMap{{"error", err.Error()}})
+}
+
+// StatusCode maps the error returned by ReadRequest to HTTP status code.
+// It returns 415 for MediaTypeError, 413 if the document is too large,
+// 400 for other errors and 200 for nil.
+func StatusCode(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+	var mediaType MediaTypeError
+	if errors.As(err, &mediaType) {
+		return http.StatusUnsupportedMediaType
+	}
+	var limit LimitError
+	if errors.As(err, &limit) && limit.Limit == "MaxDocumentSize" {
+		return http.StatusRequestEntityTooLarge
+	}
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return http.StatusRequestEntityTooLarge
+	}
+	return http.StatusBadRequest
+}
@@ -0,0 +1,152 @@
+package jzon
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestReadRequest(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		limits      Limits
+		wantStatus  int
+	}{
+		{"1", "application/json", `{"a": 1}`, Limits{}, http.StatusOK},
+		{"2", "application/json; charset=utf-8", `[1, 2]`, Limits{}, http.StatusOK},
+		{"3", "application/merge-patch+json", `{}`, Limits{}, http.StatusOK},
+		{"4", "text/plain", `{}`, Limits{}, http.StatusUnsupportedMediaType},
+		{"5", "", `{}`, Limits{}, http.StatusUnsupportedMediaType},
+		{"6", "application/json", `{"a": }`, Limits{}, http.StatusBadRequest},
+		{"7", "application/json", ``, Limits{}, http.StatusBadRequest},
+		{"8", "application/json", `[1, 2, 3, 4]`, Limits{MaxDocumentSize: 8}, http.StatusRequestEntityTooLarge},
+		{"9", "application/json", `[[[1]]]`, Limits{MaxDepth: 2}, http.StatusBadRequest},
+		{"10", "application/json", `[1]`, Limits{MaxDocumentSize: 3}, http.StatusOK},
+		{"11", "application/json", `{"a":1} trailing garbage`, Limits{}, http.StatusBadRequest},
+		{"12", "application/json", `[1]]`, Limits{}, http.StatusBadRequest},
+		{"13", "application/json", `1 2`, Limits{}, http.StatusBadRequest},
+		{"14", "application/json", " {\"a\": 1}\r\n", Limits{}, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				r.Header.Set("Content-Type", tt.contentType)
+			}
+			json, err := ReadRequest(r, tt.limits)
+			if got := StatusCode(err); got != tt.wantStatus {
+				t.Errorf("StatusCode(ReadRequest()) = %v, want %v, error = %v", got, tt.wantStatus, err)
+			}
+			if err == nil && json.String() != tt.body {
+				t.Errorf("ReadRequest() = %v, want %v", json, tt.body)
+			}
+		})
+	}
+}
+
+func TestReadRequest_DefaultMaxSize(t *testing.T) {
+	body := "[" + strings.Repeat(" ", DefaultMaxRequestSize) + "1]"
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/json")
+	_, err := ReadRequest(r, Limits{})
+	var tooLarge *http.MaxBytesError
+	if !errors.As(err, &tooLarge) {
+		t.Errorf("ReadRequest() error = %v, want *http.MaxBytesError", err)
+	}
+	if got := StatusCode(err); got != http.StatusRequestEntityTooLarge {
+		t.Errorf("StatusCode(ReadRequest()) = %v, want %v", got, http.StatusRequestEntityTooLarge)
+	}
+}
+
+func TestWriteResponse(t *testing.T) {
+	w := httptest.NewRecorder()
+	err := WriteResponse(w, http.StatusCreated, OrderedMap{{"id", 1}, {"doc", FromString(`{"b": [1, 2]}`)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w.Code != http.StatusCreated {
+		t.Errorf("WriteResponse() status = %v, want %v", w.Code, http.StatusCreated)
+	}
+	if got := w.Header().Get("Content-Type"); got != "application/json; charset=utf-8" {
+		t.Errorf("WriteResponse() Content-Type = %v", got)
+	}
+	if got, want := w.Body.String(), `{"id":1,"doc":{"b": [1, 2]}}`; got != want {
+		t.Errorf("WriteResponse() body = %v, want %v", got, want)
+	}
+
+	w = httptest.NewRecorder()
+	if err := WriteResponse(w, http.StatusOK, []interface{}{make(chan int)}); err == nil {
+		t.Errorf("WriteResponse() of chan error = nil, want error")
+	}
+	if w.Body.Len() != 0 {
+		t.Errorf("WriteResponse() wrote %v on error", w.Body.String())
+	}
+}
+
+func TestWriteError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json, err := ReadRequest(r, Limits{MaxDocumentSize: 16})
+		if err != nil {
+			WriteError(w, err)
+			return
+		}
+		WriteResponse(w, http.StatusOK, OrderedMap{{"name", json.GetString("", "name")}})
+	}))
+	defer ts.Close()
+
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+		wantBody   string
+	}{
+		{"1", `{"name": "n"}`, http.StatusOK, `{"name":"n"}`},
+		{"2", `{"name": "a long name"}`, http.StatusRequestEntityTooLarge, `{"error":"jzon: exceeded MaxDocumentSize limit 16, index 16"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := http.Post(ts.URL, "application/json", strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("status = %v, want %v", resp.StatusCode, tt.wantStatus)
+			}
+			json, err := FromReader(resp.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if json.String() != tt.wantBody {
+				t.Errorf("body = %v, want %v", json, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestStatusCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"1", nil, http.StatusOK},
+		{"2", MediaTypeError{"text/plain"}, http.StatusUnsupportedMediaType},
+		{"3", LimitError{"MaxDocumentSize", 1, 1}, http.StatusRequestEntityTooLarge},
+		{"4", LimitError{"MaxDepth", 1, 1}, http.StatusBadRequest},
+		{"5", &http.MaxBytesError{Limit: 1}, http.StatusRequestEntityTooLarge},
+		{"6", DuplicateKeyError{"a", 1, 2}, http.StatusBadRequest},
+		{"7", errors.New("unexpected EOF"), http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StatusCode(tt.err); got != tt.want {
+				t.Errorf("StatusCode() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,283 @@
+package jzon
+
+import (
+	stdjson "encoding/json"
+	"fmt"
+	"math/big"
+)
+
+const (
+	// inferMaxEnum is the max number of distinct strings to be an enum
+	inferMaxEnum = 8
+	// inferSchemaURI is the $schema of inferred schema
+	inferSchemaURI = "https://json-schema.org/draft/2020-12/schema"
+	// inferNumberPrec is the precision to compare numbers, it is the same
+	// as the schema package
+	inferNumberPrec = 256
+)
+
+// InferSchema returns a JSON Schema (draft 2020-12) which describes the
+// samples, every sample is checked strictly and merged into the schema.
+//
+// The schema is inferred from what is observed:
+//
+//	type:     the types of values, "integer" is used if all numbers are
+//	          integers like IsInteger, and "null" is added if the value
+//	          is nullable
+//	format:   "int64" if all numbers are integers in plain notation
+//	          which fit in int64, so they can be decoded into int64
+//	required: the keys which are present in every object
+//	enum:     the strings if there are at most 8 distinct values and
+//	          every value is seen twice on average
+//	minimum,
+//	maximum:  the range of numbers
+//
+// The items of arrays are merged into one schema. The properties keep
+// the order as they first appear in samples.
+func InferSchema(samples ...*JSON) (*JSON, error) {
+	root := &inferNode{}
+	for i, sample := range samples {
+		v, err := sample.validView()
+		if err != nil {
+			return nil, fmt.Errorf("InferSchema: sample %d: %v", i, err)
+		}
+		if err := root.observe(v); err != nil {
+			return nil, fmt.Errorf("InferSchema: sample %d: %v", i, err)
+		}
+	}
+
+	schema := OrderedMap{{"$schema", inferSchemaURI}}
+	schema = append(schema, root.schema()...)
+	data, err := MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return FromBytes(data), nil
+}
+
+// inferNode is the observed values at a location of samples
+type inferNode struct {
+	nulls    int
+	bools    int
+	strings  int
+	numbers  int
+	integers int
+	objects  int
+	arrays   int
+	// int64s is the number of integers in plain notation in int64 range
+	int64s int
+
+	// enum is the distinct strings, it is nil after exceeding inferMaxEnum
+	enum     []string
+	enumOver bool
+
+	min, max       *big.Float
+	minRaw, maxRaw string
+
+	properties map[string]*inferNode
+	keys       []string
+	// present is the number of objects in which the key is present
+	present map[string]int
+
+	items *inferNode
+}
+
+func (n *inferNode) observe(json *JSON) error {
+	switch kind := json.Kind(); kind {
+	case Null:
+		n.nulls++
+	case Bool:
+		n.bools++
+	case String:
+		s, err := json.ParseString()
+		if err != nil {
+			return err
+		}
+		n.strings++
+		n.observeString(s)
+	case Number:
+		b, fl, err := json.number("InferSchema", "number")
+		if err != nil {
+			return err
+		}
+		f, err := parseBigFloat("InferSchema", b, inferNumberPrec)
+		if err != nil {
+			return err
+		}
+		raw := string(b)
+		if n.numbers == 0 || f.Cmp(n.min) < 0 {
+			n.min, n.minRaw = f, raw
+		}
+		if n.numbers == 0 || f.Cmp(n.max) > 0 {
+			n.max, n.maxRaw = f, raw
+		}
+		n.numbers++
+		if json.IsInteger() {
+			n.integers++
+		}
+		// 1.0 and 1e3 are integers, but encoding/json can not decode
+		// them into int64
+		if !contains(fl, flagIsFloat) && !contains(fl, flagIsScientific) {
+			if _, err := parseIntBytes("InferSchema", b); err == nil {
+				n.int64s++
+			}
+		}
+	case Object:
+		return n.observeObject(json)
+	case Array:
+		return n.observeArray(json)
+	default:
+		return fmt.Errorf("Can not infer %s JSON", kind)
+	}
+	return nil
+}
+
+func (n *inferNode) observeString(s string) {
+	if n.enumOver {
+		return
+	}
+	for _, e := range n.enum {
+		if e == s {
+			return
+		}
+	}
+	if len(n.enum) == inferMaxEnum {
+		n.enum, n.enumOver = nil, true
+		return
+	}
+	n.enum = append(n.enum, s)
+}
+
+func (n *inferNode) observeObject(json *JSON) error {
+	iter, err := json.UnsafeObject()
+	if err != nil {
+		return err
+	}
+	if n.properties == nil {
+		n.properties = make(map[string]*inferNode)
+		n.present = make(map[string]int)
+	}
+	n.objects++
+	// duplicate keys are counted once
+	seen := make(map[string]bool)
+	for iter.Next() {
+		key := iter.Key()
+		p, ok := n.properties[key]
+		if !ok {
+			p = &inferNode{}
+			n.properties[key] = p
+			n.keys = append(n.keys, key)
+		}
+		if !seen[key] {
+			seen[key] = true
+			n.present[key]++
+		}
+		if err := p.observe(iter.Value()); err != nil {
+			return err
+		}
+	}
+	return iter.Err()
+}
+
+func (n *inferNode) observeArray(json *JSON) error {
+	iter, err := json.UnsafeArray()
+	if err != nil {
+		return err
+	}
+	n.arrays++
+	if n.items == nil {
+		n.items = &inferNode{}
+	}
+	for iter.Next() {
+		if err := n.items.observe(iter.Value()); err != nil {
+			return err
+		}
+	}
+	return iter.Err()
+}
+
+// schema returns the members of schema which describes n
+func (n *inferNode) schema() OrderedMap {
+	s := OrderedMap{}
+	if n.count() == 0 {
+		return s
+	}
+
+	var types []interface{}
+	if n.objects > 0 {
+		types = append(types, "object")
+	}
+	if n.arrays > 0 {
+		types = append(types, "array")
+	}
+	if n.strings > 0 {
+		types = append(types, "string")
+	}
+	if n.numbers > 0 {
+		if n.integers == n.numbers {
+			types = append(types, "integer")
+		} else {
+			types = append(types, "number")
+		}
+	}
+	if n.bools > 0 {
+		types = append(types, "boolean")
+	}
+	if n.nulls > 0 {
+		types = append(types, "null")
+	}
+	if len(types) == 1 {
+		s = append(s, MapItem{"type", types[0]})
+	} else {
+		s = append(s, MapItem{"type", types})
+	}
+	if n.numbers > 0 && n.int64s == n.numbers {
+		s = append(s, MapItem{"format", "int64"})
+	}
+
+	if n.objects > 0 {
+		properties := make(OrderedMap, 0, len(n.keys))
+		var required []interface{}
+		for _, key := range n.keys {
+			properties = append(properties, MapItem{key, n.properties[key].schema()})
+			if n.present[key] == n.objects {
+				required = append(required, key)
+			}
+		}
+		s = append(s, MapItem{"properties", properties})
+		if len(required) > 0 {
+			s = append(s, MapItem{"required", required})
+		}
+	}
+
+	if n.arrays > 0 && n.items.count() > 0 {
+		s = append(s, MapItem{"items", n.items.schema()})
+	}
+
+	// enum is only used if the value is a string or null,
+	// it restricts all types
+	onlyString := n.strings+n.nulls == n.count()
+	if onlyString && n.enum != nil && n.strings >= 2*len(n.enum) {
+		enum := make([]interface{}, 0, len(n.enum)+1)
+		for _, e := range n.enum {
+			enum = append(enum, e)
+		}
+		if n.nulls > 0 {
+			enum = append(enum, nil)
+		}
+		s = append(s, MapItem{"enum", enum})
+	}
+
+	if n.numbers > 0 {
+		s = append(s,
+			MapItem{"minimum", stdjson.Number(n.minRaw)},
+			MapItem{"maximum", stdjson.Number(n.maxRaw)},
+		)
+	}
+	return s
+}
+
+// count returns the number of values observed
+func (n *inferNode) count() int {
+	return n.nulls + n.bools + n.strings + n.numbers + n.objects + n.arrays
+}
@@ -0,0 +1,142 @@
+package jzon
+
+import (
+	"bytes"
+	stdjson "encoding/json"
+	"testing"
+)
+
+func TestInferSchema(t *testing.T) {
+	tests := []struct {
+		name    string
+		samples []string
+		want    string
+	}{
+		{
+			"scalar",
+			[]string{`1`, `2.5`, `-3`},
+			`{"$schema": "https://json-schema.org/draft/2020-12/schema", "type": "number", "minimum": -3, "maximum": 2.5}`,
+		},
+		{
+			"integer",
+			[]string{`10`, `-3`, `0`},
+			`{"$schema": "https://json-schema.org/draft/2020-12/schema", "type": "integer", "format": "int64", "minimum": -3, "maximum": 10}`,
+		},
+		{
+			"integer with fraction or exponent",
+			[]string{`10`, `1.0`, `2e1`},
+			`{"$schema": "https://json-schema.org/draft/2020-12/schema", "type": "integer", "minimum": 1.0, "maximum": 2e1}`,
+		},
+		{
+			"integer out of int64",
+			[]string{`1`, `9223372036854775808`},
+			`{"$schema": "https://json-schema.org/draft/2020-12/schema", "type": "integer", "minimum": 1, "maximum": 9223372036854775808}`,
+		},
+		{
+			"out of float64",
+			[]string{`1e400`, `-1e400`, `1`},
+			`{"$schema": "https://json-schema.org/draft/2020-12/schema", "type": "integer", "minimum": -1e400, "maximum": 1e400}`,
+		},
+		{
+			"exponent overflow",
+			[]string{`-1e-99999999999`, `1e-99999999999`, `1e99999999999`},
+			`{"$schema": "https://json-schema.org/draft/2020-12/schema", "type": "number", "minimum": -1e-99999999999, "maximum": 1e99999999999}`,
+		},
+		{
+			"beyond float64 precision",
+			[]string{`9007199254740993.5`, `9007199254740993`},
+			`{"$schema": "https://json-schema.org/draft/2020-12/schema", "type": "number", "minimum": 9007199254740993, "maximum": 9007199254740993.5}`,
+		},
+		{
+			"nullable",
+			[]string{`"a"`, `null`, `true`},
+			`{"$schema": "https://json-schema.org/draft/2020-12/schema", "type": ["string", "boolean", "null"]}`,
+		},
+		{
+			"required and optional",
+			[]string{`{"id": 1, "name": "a"}`, `{"id": 2, "tag": null}`},
+			`{"$schema": "https://json-schema.org/draft/2020-12/schema", "type": "object", "properties": {
+				"id": {"type": "integer", "format": "int64", "minimum": 1, "maximum": 2},
+				"name": {"type": "string"},
+				"tag": {"type": "null"}
+			}, "required": ["id"]}`,
+		},
+		{
+			"enum",
+			[]string{`["admin", "user", "user", null]`, `["admin"]`},
+			`{"$schema": "https://json-schema.org/draft/2020-12/schema", "type": "array", "items": {
+				"type": ["string", "null"], "enum": ["admin", "user", null]
+			}}`,
+		},
+		{
+			"not enum",
+			[]string{`["a", "b", "c", "a"]`},
+			`{"$schema": "https://json-schema.org/draft/2020-12/schema", "type": "array", "items": {"type": "string"}}`,
+		},
+		{
+			"too many enum",
+			[]string{`["a", "b", "c", "d", "e", "f", "g", "h", "i", "a", "b", "c", "d", "e", "f", "g", "h", "i"]`},
+			`{"$schema": "https://json-schema.org/draft/2020-12/schema", "type": "array", "items": {"type": "string"}}`,
+		},
+		{
+			"empty array",
+			[]string{`{"a": []}`},
+			`{"$schema": "https://json-schema.org/draft/2020-12/schema", "type": "object", "properties": {
+				"a": {"type": "array"}
+			}, "required": ["a"]}`,
+		},
+		{
+			"nested",
+			[]string{`{"users": [{"id": 1, "tags": ["x"]}, {"id": 2, "tags": []}]}`, `{"users": [{"id": 3}]}`},
+			`{"$schema": "https://json-schema.org/draft/2020-12/schema", "type": "object", "properties": {
+				"users": {"type": "array", "items": {"type": "object", "properties": {
+					"id": {"type": "integer", "format": "int64", "minimum": 1, "maximum": 3},
+					"tags": {"type": "array", "items": {"type": "string"}}
+				}, "required": ["id"]}}
+			}, "required": ["users"]}`,
+		},
+		{
+			"mixed",
+			[]string{`{"a": 1}`, `[1]`, `"x"`},
+			`{"$schema": "https://json-schema.org/draft/2020-12/schema", "type": ["object", "array", "string"], "properties": {
+				"a": {"type": "integer", "format": "int64", "minimum": 1, "maximum": 1}
+			}, "required": ["a"], "items": {"type": "integer", "format": "int64", "minimum": 1, "maximum": 1}}`,
+		},
+		{
+			"no sample",
+			nil,
+			`{"$schema": "https://json-schema.org/draft/2020-12/schema"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var samples []*JSON
+			for _, s := range tt.samples {
+				samples = append(samples, FromString(s))
+			}
+			got, err := InferSchema(samples...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !equalJSON(got, FromString(tt.want)) {
+				t.Errorf("InferSchema() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInferSchema_Error(t *testing.T) {
+	if _, err := InferSchema(FromString(`{}`), FromString(`{"a": }`)); err == nil {
+		t.Errorf("InferSchema() error = nil, want error")
+	}
+}
+
+// equalJSON reports whether a and b are equal ignoring whitespaces,
+// the order of keys matters
+func equalJSON(a, b *JSON) bool {
+	var x, y bytes.Buffer
+	if stdjson.Compact(&x, a.RawMessage()) != nil || stdjson.Compact(&y, b.RawMessage()) != nil {
+		return false
+	}
+	return x.String() == y.String()
+}
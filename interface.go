@@ -0,0 +1,214 @@
+package jzon
+
+// InterfaceOptions controls how InterfaceWith materializes the json value
+type InterfaceOptions struct {
+	// UseNumber represents Number as json.Number of encoding/json
+	// instead of float64, so large integers keep their precision
+	UseNumber bool
+	// Ordered represents Object as OrderedMap instead of
+	// map[string]interface{}, so the keys keep their order
+	Ordered bool
+}
+
+// MapItem is a member of OrderedMap
+type MapItem struct {
+	Key   string
+	Value interface{}
+}
+
+// OrderedMap is an object whose members are in the order
+// as they appear in document
+type OrderedMap []MapItem
+
+// Get returns the value of the last member with key
+func (m OrderedMap) Get(key string) (interface{}, bool) {
+	for i := len(m) - 1; i >= 0; i-- {
+		if m[i].Key == key {
+			return m[i].Value, true
+		}
+	}
+	return nil, false
+}
+
+// Interface materializes the json value to native go value like
+// encoding/json does, the result is one of map[string]interface{},
+// []interface{}, string, float64, bool and nil.
+func (json *JSON) Interface() (interface{}, error) {
+	return json.InterfaceWith(InterfaceOptions{})
+}
+
+// InterfaceWith is like Interface, but the representation of Number
+// and Object is controlled by options. The value is checked strictly
+// before materializing, the Limits and DuplicateKeyPolicy are honored.
+// If duplicate keys are allowed, the last one wins in map and all of
+// them are kept in OrderedMap.
+func (json *JSON) InterfaceWith(options InterfaceOptions) (interface{}, error) {
+	v, err := json.validView()
+	if err != nil {
+		return nil, err
+	}
+	return v.materialize(options)
+}
+
+// materializeFrame is an open object or array in materialize
+type materializeFrame struct {
+	object bool
+	// needKey is true if the next string is a key of object
+	needKey bool
+	key     string
+	m       map[string]interface{}
+	om      OrderedMap
+	// index is the position of keys in om, it is used to
+	// drop the duplicate keys
+	index   map[string]int
+	dropped map[int]bool
+	a       []interface{}
+}
+
+func (f *materializeFrame) value() interface{} {
+	switch {
+	case !f.object:
+		return f.a
+	case f.om != nil:
+		if len(f.dropped) == 0 {
+			return f.om
+		}
+		om := make(OrderedMap, 0, len(f.om)-len(f.dropped))
+		for i, item := range f.om {
+			if !f.dropped[i] {
+				om = append(om, item)
+			}
+		}
+		return om
+	}
+	return f.m
+}
+
+// set adds the member of object, duplicate keys are handled by policy
+func (f *materializeFrame) set(policy DuplicateKeyPolicy, v interface{}) {
+	if f.om == nil {
+		if _, ok := f.m[f.key]; ok && policy == DuplicateKeyFirstWins {
+			return
+		}
+		f.m[f.key] = v
+		return
+	}
+	if policy == DuplicateKeyAllow {
+		f.om = append(f.om, MapItem{f.key, v})
+		return
+	}
+	if i, ok := f.index[f.key]; ok {
+		if policy == DuplicateKeyFirstWins {
+			return
+		}
+		// the last one wins at its position
+		if f.dropped == nil {
+			f.dropped = make(map[int]bool)
+		}
+		f.dropped[i] = true
+	}
+	f.index[f.key] = len(f.om)
+	f.om = append(f.om, MapItem{f.key, v})
+}
+
+// materialize converts the valid json value to native go value, it scans
+// json once with an explicit stack, so the deep nesting costs neither
+// the goroutine stack nor scanning the children again.
+//
+// It does not use ObjectIter and ArrayIter on purpose: an iterator works
+// on the view of one value, so building the tree with them recurses once
+// per level and scans every nested value again for each of its parents,
+// which overflows the stack and is quadratic on deep input.
+func (json *JSON) materialize(options InterfaceOptions) (interface{}, error) {
+	scan := json.value()
+	var stack []*materializeFrame
+	for {
+		if !scan.checkContext() {
+			return nil, scan.err
+		}
+		c, ok := scan.nextToken()
+		if !ok {
+			return nil, SyntaxError{Invalid, scan.offset, scan.data}
+		}
+		head := scan.offset
+		var v interface{}
+		switch c {
+		case '{':
+			f := &materializeFrame{object: true, needKey: true}
+			if options.Ordered {
+				f.om = OrderedMap{}
+				f.index = make(map[string]int)
+			} else {
+				f.m = make(map[string]interface{})
+			}
+			stack = append(stack, f)
+			scan.offset++
+			continue
+		case '[':
+			stack = append(stack, &materializeFrame{a: make([]interface{}, 0)})
+			scan.offset++
+			continue
+		case ',':
+			if top := stack[len(stack)-1]; top.object {
+				top.needKey = true
+			}
+			scan.offset++
+			continue
+		case ':':
+			scan.offset++
+			continue
+		case '}', ']':
+			v = stack[len(stack)-1].value()
+			stack = stack[:len(stack)-1]
+			scan.offset++
+		case '"':
+			end := scan.validStringEnd()
+			if end == -1 {
+				return nil, scan.err
+			}
+			str, _ := unquote(scan.data[head:end])
+			scan.offset = end
+			if n := len(stack); n > 0 && stack[n-1].needKey {
+				stack[n-1].key = str
+				stack[n-1].needKey = false
+				continue
+			}
+			v = str
+		case 't', 'f', 'n':
+			end := scan.validLiteralValueEnd()
+			if end == -1 {
+				return nil, scan.err
+			}
+			if c != 'n' {
+				v = c == 't'
+			}
+			scan.offset = end
+		default:
+			end := scan.unsafeNumberEnd()
+			if end == -1 {
+				return nil, scan.err
+			}
+			n := scan.Clone()
+			n.head, n.tail, n.offset = head, end, head
+			var err error
+			if options.UseNumber {
+				v, err = n.ParseNumber()
+			} else {
+				v, err = n.ParseFloat()
+			}
+			if err != nil {
+				return nil, err
+			}
+			scan.offset = end
+		}
+
+		if len(stack) == 0 {
+			return v, nil
+		}
+		if top := stack[len(stack)-1]; top.object {
+			top.set(json.dupPolicy, v)
+		} else {
+			top.a = append(top.a, v)
+		}
+	}
+}
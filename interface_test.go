@@ -0,0 +1,194 @@
+package jzon
+
+import (
+	stdjson "encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestJSON_Interface(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr bool
+	}{
+		{"1", jsonStr, false},
+		{"2", `[]`, false},
+		{"3", `{}`, false},
+		{"4", ` null `, false},
+		{"5", `"aé\n"`, false},
+		{"6", `[1, -2.5e3, true, false, null, "x", {"a": [{}]}]`, false},
+		{"7", `{"a": 1, "a": 2}`, false},
+		{"8", `{"a": [1, 2}`, true},
+		{"9", `[1, 2`, true},
+		{"10", ``, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FromString(tt.data).Interface()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("JSON.Interface() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			var want interface{}
+			if err := stdjson.Unmarshal([]byte(tt.data), &want); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("JSON.Interface() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestJSON_InterfaceWith(t *testing.T) {
+	data := `{"z": 12345678901234567890, "a": [1.50], "m": {"y": null, "x": "s"}, "z": 1}`
+
+	got, err := FromString(data).InterfaceWith(InterfaceOptions{UseNumber: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{
+		"z": stdjson.Number("1"),
+		"a": []interface{}{stdjson.Number("1.50")},
+		"m": map[string]interface{}{"y": nil, "x": "s"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("JSON.InterfaceWith(UseNumber) = %v, want %v", got, want)
+	}
+
+	got, err = FromString(data).InterfaceWith(InterfaceOptions{UseNumber: true, Ordered: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantOrdered := OrderedMap{
+		{"z", stdjson.Number("12345678901234567890")},
+		{"a", []interface{}{stdjson.Number("1.50")}},
+		{"m", OrderedMap{{"y", nil}, {"x", "s"}}},
+		{"z", stdjson.Number("1")},
+	}
+	if !reflect.DeepEqual(got, wantOrdered) {
+		t.Errorf("JSON.InterfaceWith(Ordered) = %v, want %v", got, wantOrdered)
+	}
+	if v, ok := got.(OrderedMap).Get("z"); !ok || v != stdjson.Number("1") {
+		t.Errorf("OrderedMap.Get(z) = %v %v, want 1 true", v, ok)
+	}
+	if _, ok := got.(OrderedMap).Get("none"); ok {
+		t.Errorf("OrderedMap.Get(none) = true, want false")
+	}
+
+	// duplicate key policy
+	_, err = FromString(data).SetDuplicateKeyPolicy(DuplicateKeyReject).Interface()
+	if _, ok := err.(DuplicateKeyError); !ok {
+		t.Errorf("JSON.Interface() with Reject error = %v, want DuplicateKeyError", err)
+	}
+	got, err = FromString(data).SetDuplicateKeyPolicy(DuplicateKeyFirstWins).InterfaceWith(InterfaceOptions{UseNumber: true, Ordered: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m := got.(OrderedMap); len(m) != 3 || m[0].Value != stdjson.Number("12345678901234567890") {
+		t.Errorf("JSON.InterfaceWith() with FirstWins = %v", m)
+	}
+
+	// sub value
+	json := FromString(data)
+	if err := json.Path("m"); err != nil {
+		t.Fatal(err)
+	}
+	got, err = json.Interface()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want["m"]) {
+		t.Errorf("JSON.Interface() of m = %v, want %v", got, want["m"])
+	}
+
+	// limits
+	deep := strings.Repeat("[", 10) + strings.Repeat("]", 10)
+	_, err = FromString(deep).SetLimits(Limits{MaxDepth: 5}).Interface()
+	if _, ok := err.(LimitError); !ok {
+		t.Errorf("JSON.Interface() with limits error = %v, want LimitError", err)
+	}
+}
+
+func TestJSON_InterfaceWith_DuplicateKey(t *testing.T) {
+	data := `{"a": 1, "b": [2], "a": 3, "c": 4, "a": 5}`
+	tests := []struct {
+		policy  DuplicateKeyPolicy
+		want    map[string]interface{}
+		ordered OrderedMap
+	}{
+		{
+			DuplicateKeyAllow,
+			map[string]interface{}{"a": 5.0, "b": []interface{}{2.0}, "c": 4.0},
+			OrderedMap{{"a", 1.0}, {"b", []interface{}{2.0}}, {"a", 3.0}, {"c", 4.0}, {"a", 5.0}},
+		},
+		{
+			DuplicateKeyFirstWins,
+			map[string]interface{}{"a": 1.0, "b": []interface{}{2.0}, "c": 4.0},
+			OrderedMap{{"a", 1.0}, {"b", []interface{}{2.0}}, {"c", 4.0}},
+		},
+		{
+			DuplicateKeyLastWins,
+			map[string]interface{}{"a": 5.0, "b": []interface{}{2.0}, "c": 4.0},
+			OrderedMap{{"b", []interface{}{2.0}}, {"c", 4.0}, {"a", 5.0}},
+		},
+	}
+	for _, tt := range tests {
+		got, err := FromString(data).SetDuplicateKeyPolicy(tt.policy).Interface()
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("JSON.Interface() with policy %v = %v %v, want %v", tt.policy, got, err, tt.want)
+		}
+		got, err = FromString(data).SetDuplicateKeyPolicy(tt.policy).InterfaceWith(InterfaceOptions{Ordered: true})
+		if err != nil || !reflect.DeepEqual(got, tt.ordered) {
+			t.Errorf("JSON.InterfaceWith(Ordered) with policy %v = %v %v, want %v", tt.policy, got, err, tt.ordered)
+		}
+	}
+}
+
+func TestJSON_Interface_Trailing(t *testing.T) {
+	for _, data := range []string{`{"a":1} trailing`, `[1]]`, `1 2`, `"a" "b"`} {
+		if got, err := FromString(data).Interface(); err == nil {
+			t.Errorf("JSON.Interface() of %s = %v, want error", data, got)
+		}
+	}
+	if got, err := FromString(" \n[1] \t").Interface(); err != nil || len(got.([]interface{})) != 1 {
+		t.Errorf("JSON.Interface() with whitespaces = %v %v", got, err)
+	}
+}
+
+func TestJSON_Interface_DeepNesting(t *testing.T) {
+	n := 100000
+	data := strings.Repeat(`{"a":[`, n) + "1" + strings.Repeat("]}", n)
+	got, err := FromString(data).Interface()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < n; i++ {
+		got = got.(map[string]interface{})["a"].([]interface{})[0]
+	}
+	if got != 1.0 {
+		t.Errorf("JSON.Interface() of deep nesting = %v, want 1", got)
+	}
+}
+
+func BenchmarkJSON_Interface(b *testing.B) {
+	data := []byte(jsonStr)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		FromBytes(data).Interface()
+	}
+}
+
+func BenchmarkStdJSON_Unmarshal_Interface(b *testing.B) {
+	data := []byte(jsonStr)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var v interface{}
+		stdjson.Unmarshal(data, &v)
+	}
+}
@@ -3,56 +3,110 @@ package jzon
 // ObjectIter is an iterable on object type JSON
 type ObjectIter struct {
 	*JSON
-	key       string
-	len       int
-	keysCache []string
+	key string
+	// keyRaw is the quoted key, it is unquoted to key lazily
+	keyRaw     []byte
+	keyDecoded bool
+	keyOffset  int
+	len        int
+	keysCache  []string
+	// skip contains the offsets of keys ignored by the DuplicateKeyPolicy
+	skip map[int]bool
+	// state is the expected tokens, zero means the start of object
+	state flag
+	done  bool
 }
 
 // Reset resets the ObjectIter then you can use it again
 func (iter *ObjectIter) Reset() {
 	iter.offset = 0
+	iter.err = nil
+	iter.state = 0
+	iter.done = false
 	iter.key = ""
+	iter.keyRaw = nil
+	iter.keyDecoded = true
 }
 
 // Next finds the next key and value pair of object.
 // If not return false
 // example:
-// for iter.Next() {
-// 	  key := iter.Key()
-// 	  value := iter.Value()
-// 	  // do something
-// }
+//
+//	for iter.Next() {
+//		key := iter.Key()
+//		value := iter.Value()
+//		// do something
+//	}
+//
+//	if err := iter.Err(); err != nil {
+//		// the object is malformed
+//	}
 func (iter *ObjectIter) Next() bool {
-Loop:
+	if iter.err != nil || iter.done {
+		return false
+	}
 	for {
+		if !iter.checkContext() {
+			return false
+		}
 		c, ok := iter.nextToken()
 		if !ok {
-			return false
+			// the object is not closed
+			return iter.fail()
 		}
-		switch c {
-		case '{':
+		switch {
+		case c == '{' && iter.state == 0:
+			iter.state = add(0, flagNeedKey, flagNeedEnd)
 			iter.offset++
-		case '"':
+		case c == '"' && contains(iter.state, flagNeedKey):
 			end := iter.validStringEnd()
-			s, _ := unquote(iter.data[iter.offset:end])
-			iter.key = string(s)
+			if end == -1 {
+				return iter.fail()
+			}
+			iter.keyRaw = iter.data[iter.offset:end]
+			iter.keyDecoded = false
+			iter.keyOffset = iter.offset
 			iter.offset = end
-		case ':':
+			iter.state = flagNeedColon
+		case c == ':' && contains(iter.state, flagNeedColon):
 			iter.offset++
 			end, _ := iter.unsafeValueEnd()
+			if end == -1 {
+				return iter.fail()
+			}
 			iter.head = iter.offset
 			iter.tail = end
 			iter.offset = end
-			break Loop
-		case ',':
+			iter.state = add(0, flagNeedComma, flagNeedEnd)
+			if iter.skip[iter.keyOffset] {
+				continue
+			}
+			return true
+		case c == ',' && contains(iter.state, flagNeedComma):
 			iter.offset++
-		case '}':
+			iter.state = flagNeedKey
+		case c == '}' && contains(iter.state, flagNeedEnd):
 			iter.offset++
+			iter.done = true
 			return false
+		default:
+			return iter.fail()
 		}
+	}
+}
 
+// fail stops the iteration on syntax error
+func (iter *ObjectIter) fail() bool {
+	if iter.err == nil {
+		iter.err = SyntaxError{Object, iter.offset, iter.data}
 	}
-	return true
+	return false
+}
+
+// Err returns the syntax error which stops Next,
+// it is nil if the iteration ends normally
+func (iter *ObjectIter) Err() error {
+	return iter.err
 }
 
 // Len returns the length of object using Next() api
@@ -70,12 +124,33 @@ func (iter *ObjectIter) Len() int {
 
 // Key returns current key
 func (iter *ObjectIter) Key() string {
+	if !iter.keyDecoded {
+		iter.key, _ = unquote(iter.keyRaw)
+		iter.keyDecoded = true
+	}
 	return iter.key
 }
 
-// Value returns current value
+// KeyBytes returns current key without allocation if possible.
+// If the key contains no escapes, the returned slice refers to the
+// underlying data and must not be modified, otherwise the key is
+// decoded into buf[:0].
+func (iter *ObjectIter) KeyBytes(buf []byte) []byte {
+	b, _ := unquoteInto(buf, iter.keyRaw)
+	return b
+}
+
+// KeyEquals reports whether current key is equal to s,
+// escaped key is compared without allocation.
+func (iter *ObjectIter) KeyEquals(s string) bool {
+	return unquoteEqual(iter.keyRaw, s)
+}
+
+// Value returns current value, it is a detached view which is not
+// changed by the following calls of Next, so it can be collected
+// or sent to other goroutines
 func (iter *ObjectIter) Value() *JSON {
-	return iter.JSON
+	return iter.value()
 }
 
 // Keys returns all keys and store in cache
@@ -86,7 +161,7 @@ func (iter *ObjectIter) Keys() []string {
 	if len(iter.keysCache) == 0 {
 		iter.len = 0
 		for iter.Next() {
-			iter.keysCache = append(iter.keysCache, iter.key)
+			iter.keysCache = append(iter.keysCache, iter.Key())
 			iter.len++
 		}
 		iter.Reset()
@@ -111,44 +186,83 @@ type ArrayIter struct {
 	*JSON
 	index int
 	len   int
+	// state is the expected tokens, zero means the start of array
+	state flag
+	// table is the head and tail of every element,
+	// it is built lazily for random access
+	table [][2]int
 }
 
 // Reset resets the ArrayIter then you can use it again
 func (iter *ArrayIter) Reset() {
 	iter.offset = 0
+	iter.err = nil
+	iter.state = 0
 	iter.index = -1
 }
 
 // Next finds the next value of array.
 // If not return false
 // example:
-// for iter.Next() {
-// 	  index := iter.Index()
-// 	  value := iter.Value()
-// 	  // do something
-// }
+//
+//	for iter.Next() {
+//		index := iter.Index()
+//		value := iter.Value()
+//		// do something
+//	}
+//
+//	if err := iter.Err(); err != nil {
+//		// the array is malformed
+//	}
 func (iter *ArrayIter) Next() bool {
-Loop:
+	if iter.err != nil {
+		return false
+	}
 	for {
+		if !iter.checkContext() {
+			return false
+		}
 		c, ok := iter.nextToken()
 		if !ok {
+			if iter.state == flagNeedValue {
+				// trailing comma
+				return iter.fail()
+			}
 			return false
 		}
-		switch c {
-		case ',':
+		switch {
+		case c == ',' && contains(iter.state, flagNeedComma):
 			iter.offset++
-		default:
+			iter.state = flagNeedValue
+		case c != ',' && (iter.state == 0 || contains(iter.state, flagNeedValue)):
 			end, _ := iter.unsafeValueEnd()
+			if end == -1 {
+				return iter.fail()
+			}
 			iter.index++
 			iter.head = iter.offset
 			iter.tail = end
 			iter.offset = end
-
-			break Loop
+			iter.state = flagNeedComma
+			return true
+		default:
+			return iter.fail()
 		}
+	}
+}
 
+// fail stops the iteration on syntax error
+func (iter *ArrayIter) fail() bool {
+	if iter.err == nil {
+		iter.err = SyntaxError{Array, iter.offset, iter.data}
 	}
-	return true
+	return false
+}
+
+// Err returns the syntax error which stops Next,
+// it is nil if the iteration ends normally
+func (iter *ArrayIter) Err() error {
+	return iter.err
 }
 
 // Len returns the length of object using Next() api
@@ -169,9 +283,87 @@ func (iter *ArrayIter) Index() int {
 	return iter.index
 }
 
-// Value returns current value
+// Value returns current value, it is a detached view like ObjectIter.Value
 func (iter *ArrayIter) Value() *JSON {
-	return iter.JSON
+	return iter.value()
+}
+
+// Seek moves iter to the element at index n, Value and Index return it
+// and Next continues from the element after it. Negative n counts from
+// the end of array, -1 is the last element. It returns false if n is out
+// of range or the array is malformed, iter is not moved in that case.
+//
+// The first call scans the whole array and builds an offset table of
+// elements, after that Seek, Skip and Last are O(1).
+func (iter *ArrayIter) Seek(n int) bool {
+	if !iter.buildTable() {
+		return false
+	}
+	if n < 0 {
+		n += len(iter.table)
+	}
+	if n < 0 || n >= len(iter.table) {
+		return false
+	}
+	iter.head = iter.table[n][0]
+	iter.tail = iter.table[n][1]
+	iter.offset = iter.tail
+	iter.index = n
+	iter.state = flagNeedComma
+	return true
+}
+
+// Skip moves iter forward by n elements like calling Next n times, or
+// backward if n is negative. It returns false if the target element is
+// out of range or the array is malformed, iter is not moved in that case
+// like Seek, and Err reports the malformed array.
+func (iter *ArrayIter) Skip(n int) bool {
+	if n < 0 || iter.table != nil {
+		if iter.index+n < 0 {
+			return false
+		}
+		return iter.Seek(iter.index + n)
+	}
+	if n == 0 {
+		return iter.index >= 0
+	}
+	offset, index, head, tail, state := iter.offset, iter.index, iter.head, iter.tail, iter.state
+	for ; n > 0; n-- {
+		if !iter.Next() {
+			iter.offset, iter.index, iter.head, iter.tail, iter.state = offset, index, head, tail, state
+			return false
+		}
+	}
+	return true
+}
+
+// Last moves iter to the last element, it returns false if the array is
+// empty or malformed
+func (iter *ArrayIter) Last() bool {
+	return iter.Seek(-1)
+}
+
+// buildTable scans all elements into iter.table, the state of iter is kept
+func (iter *ArrayIter) buildTable() bool {
+	if iter.table != nil {
+		return true
+	}
+	offset, index, head, tail, state := iter.offset, iter.index, iter.head, iter.tail, iter.state
+	defer func() {
+		iter.offset, iter.index, iter.head, iter.tail, iter.state = offset, index, head, tail, state
+	}()
+
+	iter.Reset()
+	table := make([][2]int, 0, iter.len)
+	for iter.Next() {
+		table = append(table, [2]int{iter.head, iter.tail})
+	}
+	if iter.err != nil {
+		return false
+	}
+	iter.table = table
+	iter.len = len(table)
+	return true
 }
 
 // ----------------------------------------------------------------------------
@@ -197,9 +389,31 @@ func (json *JSON) objectIter(valify bool) (*ObjectIter, error) {
 		}
 	}
 
-	return &ObjectIter{
-		JSON: FromBytes(json.data[json.head:json.tail]),
-	}, nil
+	if json.limits != (Limits{}) {
+		// make sure the object does not exceed the limits
+		if !json.checkDocumentSize() || json.unsafeObjectEnd() == -1 {
+			return nil, json.err
+		}
+	}
+
+	var skip map[int]bool
+	if json.dupPolicy != DuplicateKeyAllow {
+		var err error
+		skip, err = json.duplicateKeys()
+		if err != nil {
+			json.err = err
+			return nil, err
+		}
+	}
+
+	iter := &ObjectIter{
+		JSON: json.sub(json.data[json.head:json.tail]),
+		skip: skip,
+	}
+	if n, ok := json.node(); ok && skip == nil {
+		iter.len = n.count
+	}
+	return iter, nil
 }
 
 // Object returns an ObjectIter which is an iterable on the object after valified.
@@ -222,6 +436,7 @@ func (json *JSON) arrayIter(valify bool) (*ArrayIter, error) {
 	json.offset = json.head
 
 	json.mustBe(Array)
+	start := json.offset
 	if json.tail <= 0 {
 		if valify {
 			json.tail = json.validArrayEnd()
@@ -232,10 +447,33 @@ func (json *JSON) arrayIter(valify bool) (*ArrayIter, error) {
 			return nil, json.err
 		}
 	}
-	return &ArrayIter{
-		JSON:  FromBytes(json.data[json.head+1 : json.tail-1]),
+
+	if json.limits != (Limits{}) {
+		// make sure the array does not exceed the limits
+		if !json.checkDocumentSize() || json.unsafeArrayEnd() == -1 {
+			return nil, json.err
+		}
+	}
+
+	// the elements are between brackets, the whitespaces
+	// around the array are not trimmed in some cases
+	end := json.tail
+	for end > start && isSpace(json.data[end-1]) {
+		end--
+	}
+	if end-start < 2 || json.data[end-1] != ']' {
+		json.err = SyntaxError{Array, end, json.data}
+		return nil, json.err
+	}
+
+	iter := &ArrayIter{
+		JSON:  json.sub(json.data[start+1 : end-1]),
 		index: -1,
-	}, nil
+	}
+	if n, ok := json.node(); ok {
+		iter.len = n.count
+	}
+	return iter, nil
 }
 
 // Array returns an ArrayIter which is an iterable on the array after valified.
@@ -0,0 +1,18 @@
+//go:build go1.18
+// +build go1.18
+
+package jzon
+
+import "testing"
+
+func FuzzIter(f *testing.F) {
+	for _, data := range iterCorpus {
+		f.Add(data)
+	}
+	for _, data := range scanCorpus {
+		f.Add(data)
+	}
+	f.Fuzz(func(t *testing.T, data string) {
+		iterateAll(t, data)
+	})
+}
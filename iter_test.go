@@ -1,6 +1,10 @@
 package jzon
 
-import "testing"
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
 
 var (
 	array  = `[1,2,3,4,5]`
@@ -94,3 +98,409 @@ func TestArrayIter_Len(t *testing.T) {
 		})
 	}
 }
+
+func TestObjectIter_DuplicateKey(t *testing.T) {
+	data := `{"a": 1, "b": 2, "a": 3}`
+	tests := []struct {
+		name    string
+		policy  DuplicateKeyPolicy
+		want    string
+		wantErr bool
+	}{
+		{"allow", DuplicateKeyAllow, "a=1 b=2 a=3 ", false},
+		{"reject", DuplicateKeyReject, "", true},
+		{"first", DuplicateKeyFirstWins, "a=1 b=2 ", false},
+		{"last", DuplicateKeyLastWins, "b=2 a=3 ", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			iter, err := FromString(data).SetDuplicateKeyPolicy(tt.policy).Object()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("JSON.Object() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			got := ""
+			for iter.Next() {
+				got += iter.Key() + "=" + iter.Value().String() + " "
+			}
+			if got != tt.want {
+				t.Errorf("ObjectIter.Next() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestObjectIter_KeyBytes(t *testing.T) {
+	iter, _ := FromString(`{"plain": 1, "esc\u0061ped": 2}`).Object()
+	buf := make([]byte, 0, 16)
+	want := []string{"plain", "escaped"}
+	i := 0
+	for iter.Next() {
+		if got := string(iter.KeyBytes(buf)); got != want[i] {
+			t.Errorf("ObjectIter.KeyBytes() = %v, want %v", got, want[i])
+		}
+		if !iter.KeyEquals(want[i]) {
+			t.Errorf("ObjectIter.KeyEquals(%v) = false, want true", want[i])
+		}
+		if iter.KeyEquals("other") {
+			t.Errorf("ObjectIter.KeyEquals(other) = true, want false")
+		}
+		if got := iter.Key(); got != want[i] {
+			t.Errorf("ObjectIter.Key() = %v, want %v", got, want[i])
+		}
+		i++
+	}
+}
+
+func BenchmarkObjectIter_Next(b *testing.B) {
+	json := FromString(jsonStr)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		iter, _ := json.Object()
+		for iter.Next() {
+			iter.KeyEquals("list")
+		}
+	}
+}
+
+// iterCorpus contains malformed objects and arrays which are accepted
+// by the unsafe iterators
+var iterCorpus = []string{
+	`{}`,
+	`[]`,
+	`{"a": 1, "b": [1, 2]}`,
+	`{"a" 1}`,
+	`{"a": 1 "b": 2}`,
+	`{"a": 1,}`,
+	`{,"a": 1}`,
+	`{"a": }`,
+	`{"a": 1`,
+	`{"a`,
+	`{a}`,
+	`{"a": -}`,
+	`{"a": 1}}`,
+	`[1 2]`,
+	`[1,,2]`,
+	`[1,]`,
+	`[,1]`,
+	`[1, "a]`,
+	`[1, x]`,
+	`[[1], {]`,
+	`[:]`,
+	`[`,
+	` [1] `,
+}
+
+func TestObjectIter_Err(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		wantKeys []string
+		wantErr  bool
+	}{
+		{"1", `{}`, nil, false},
+		{"2", `{"a": 1, "b": [1, 2]}`, []string{"a", "b"}, false},
+		{"3", `{"a" 1}`, nil, true},
+		{"4", `{"a": 1 "b": 2}`, []string{"a"}, true},
+		{"5", `{"a": 1,}`, []string{"a"}, true},
+		{"6", `{,"a": 1}`, nil, true},
+		{"7", `{"a": }`, nil, true},
+		{"8", `{"a": 1`, []string{"a"}, true},
+		{"9", `{a}`, nil, true},
+		{"10", `{"a": 1, "b`, []string{"a"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			iter := &ObjectIter{JSON: FromString(tt.data)}
+			var keys []string
+			for iter.Next() {
+				keys = append(keys, iter.Key())
+			}
+			if (iter.Err() != nil) != tt.wantErr {
+				t.Errorf("ObjectIter.Err() = %v, wantErr %v", iter.Err(), tt.wantErr)
+			}
+			if !reflect.DeepEqual(keys, tt.wantKeys) {
+				t.Errorf("ObjectIter.Next() keys = %v, want %v", keys, tt.wantKeys)
+			}
+			// Next keeps returning false
+			if iter.Next() {
+				t.Errorf("ObjectIter.Next() after end = true, want false")
+			}
+		})
+	}
+}
+
+func TestArrayIter_Err(t *testing.T) {
+	tests := []struct {
+		name       string
+		data       string
+		wantValues []string
+		wantErr    bool
+	}{
+		{"1", `[]`, nil, false},
+		{"2", `[1, "a", [2]]`, []string{`1`, `"a"`, `[2]`}, false},
+		{"3", `[1 2]`, []string{`1`}, true},
+		{"4", `[1,,2]`, []string{`1`}, true},
+		{"5", `[1,]`, []string{`1`}, true},
+		{"6", `[,1]`, nil, true},
+		{"7", `[1, "a]`, []string{`1`}, true},
+		{"8", `[:]`, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			iter, err := FromString(tt.data).UnsafeArray()
+			if err != nil {
+				t.Fatal(err)
+			}
+			var values []string
+			for iter.Next() {
+				values = append(values, iter.Value().String())
+			}
+			if (iter.Err() != nil) != tt.wantErr {
+				t.Errorf("ArrayIter.Err() = %v, wantErr %v", iter.Err(), tt.wantErr)
+			}
+			if !reflect.DeepEqual(values, tt.wantValues) {
+				t.Errorf("ArrayIter.Next() values = %v, want %v", values, tt.wantValues)
+			}
+			if iter.Next() {
+				t.Errorf("ArrayIter.Next() after end = true, want false")
+			}
+			iter.Reset()
+			if iter.Err() != nil {
+				t.Errorf("ArrayIter.Err() after Reset = %v, want nil", iter.Err())
+			}
+		})
+	}
+}
+
+// iterateAll iterates data by the unsafe iterators and returns the number
+// of values, it fails if Next does not stop
+func iterateAll(t *testing.T, data string) int {
+	n := 0
+	json := FromString(data)
+	switch json.Kind() {
+	case Object:
+		iter := &ObjectIter{JSON: json}
+		for iter.Next() {
+			iter.Key()
+			_ = iter.Value().String()
+			if n++; n > len(data) {
+				t.Fatalf("ObjectIter.Next() of %q does not stop", data)
+			}
+		}
+	case Array:
+		iter, err := json.UnsafeArray()
+		if err != nil {
+			return 0
+		}
+		for iter.Next() {
+			_ = iter.Value().String()
+			if n++; n > len(data) {
+				t.Fatalf("ArrayIter.Next() of %q does not stop", data)
+			}
+		}
+	}
+	return n
+}
+
+func TestIter_Corpus(t *testing.T) {
+	for _, data := range iterCorpus {
+		iterateAll(t, data)
+	}
+	for _, data := range scanCorpus {
+		iterateAll(t, data)
+	}
+}
+
+func TestObjectIter_Value_Detached(t *testing.T) {
+	iter, err := FromString(`{"a": 1, "b": "x", "c": [true]}`).Object()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var values []*JSON
+	for iter.Next() {
+		values = append(values, iter.Value())
+	}
+	want := []string{`1`, `"x"`, `[true]`}
+	for i, v := range values {
+		if v.String() != want[i] {
+			t.Errorf("ObjectIter.Value()[%d] = %v, want %v", i, v, want[i])
+		}
+	}
+	if got := values[2].GetBool(false, 0); !got {
+		t.Errorf("ObjectIter.Value()[2].GetBool(0) = %v, want true", got)
+	}
+	// moving a value does not affect the iterator
+	iter.Reset()
+	iter.Next()
+	if i, err := iter.Value().ParseInt64(); err != nil || i != 1 {
+		t.Errorf("ObjectIter.Value().ParseInt64() = %v %v, want 1", i, err)
+	}
+	if !iter.Next() || iter.Key() != "b" {
+		t.Errorf("ObjectIter.Next() after Value = %v, want b", iter.Key())
+	}
+}
+
+func TestArrayIter_Value_Detached(t *testing.T) {
+	iter, err := FromString(`[{"id": 1}, {"id": 2}, {"id": 3}]`).Array()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ch := make(chan *JSON, 3)
+	for iter.Next() {
+		ch <- iter.Value()
+	}
+	close(ch)
+	var ids []int64
+	for v := range ch {
+		ids = append(ids, v.GetInt(0, "id"))
+	}
+	if want := []int64{1, 2, 3}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("ArrayIter.Value() ids = %v, want %v", ids, want)
+	}
+}
+
+func TestJSON_Clone(t *testing.T) {
+	json := FromString(`{"a": {"b": 1}}`)
+	c := json.Clone()
+	if err := c.Path("a", "b"); err != nil {
+		t.Fatal(err)
+	}
+	if c.String() != "1" {
+		t.Errorf("JSON.Clone().Path() = %v, want 1", c)
+	}
+	if json.String() != `{"a": {"b": 1}}` {
+		t.Errorf("JSON.String() after Clone().Path() = %v", json)
+	}
+}
+
+func TestArrayIter_Seek(t *testing.T) {
+	iter, err := FromString(`[0, "1", [2], {"k": 3}, 4]`).Array()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name      string
+		n         int
+		want      bool
+		wantIndex int
+		wantValue string
+	}{
+		{"1", 2, true, 2, `[2]`},
+		{"2", 0, true, 0, `0`},
+		{"3", -1, true, 4, `4`},
+		{"4", -5, true, 0, `0`},
+		{"5", 5, false, 0, `0`},
+		{"6", -6, false, 0, `0`},
+		{"7", 3, true, 3, `{"k": 3}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := iter.Seek(tt.n); got != tt.want {
+				t.Errorf("ArrayIter.Seek(%d) = %v, want %v", tt.n, got, tt.want)
+			}
+			if iter.Index() != tt.wantIndex || iter.Value().String() != tt.wantValue {
+				t.Errorf("ArrayIter.Seek(%d) moves to %d %v, want %d %v", tt.n, iter.Index(), iter.Value(), tt.wantIndex, tt.wantValue)
+			}
+		})
+	}
+
+	// Next continues after Seek
+	iter.Seek(1)
+	if !iter.Next() || iter.Index() != 2 || iter.Value().String() != `[2]` {
+		t.Errorf("ArrayIter.Next() after Seek(1) = %d %v", iter.Index(), iter.Value())
+	}
+	if !iter.Last() || iter.Next() {
+		t.Errorf("ArrayIter.Next() after Last() = true, want false")
+	}
+	if iter.Len() != 5 {
+		t.Errorf("ArrayIter.Len() = %v, want 5", iter.Len())
+	}
+}
+
+func TestArrayIter_Skip(t *testing.T) {
+	iter, err := FromString(`[0, 1, 2, 3, 4]`).Array()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if iter.Skip(0) {
+		t.Errorf("ArrayIter.Skip(0) before Next = true, want false")
+	}
+	// without table
+	if !iter.Skip(3) || iter.Index() != 2 || iter.Value().String() != "2" {
+		t.Errorf("ArrayIter.Skip(3) = %d %v, want 2", iter.Index(), iter.Value())
+	}
+	if iter.table != nil {
+		t.Errorf("ArrayIter.Skip() forward builds table")
+	}
+	// backward builds table
+	if !iter.Skip(-2) || iter.Index() != 0 {
+		t.Errorf("ArrayIter.Skip(-2) = %d, want 0", iter.Index())
+	}
+	if iter.Skip(-1) {
+		t.Errorf("ArrayIter.Skip(-1) at 0 = true, want false")
+	}
+	if !iter.Skip(4) || iter.Value().String() != "4" {
+		t.Errorf("ArrayIter.Skip(4) = %d %v, want 4", iter.Index(), iter.Value())
+	}
+	if iter.Skip(1) || iter.Index() != 4 {
+		t.Errorf("ArrayIter.Skip(1) at the end = true, want false")
+	}
+}
+
+func TestArrayIter_Skip_Failed(t *testing.T) {
+	for _, tt := range []struct {
+		data    string
+		wantErr bool
+	}{
+		{`[0, 1, 2]`, false},
+		{`[0, 1, 2,]`, true},
+		{`[0, 1 2]`, true},
+	} {
+		data := tt.data
+		iter, err := FromString(data).UnsafeArray()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !iter.Next() {
+			t.Fatalf("ArrayIter.Next() of %s = false, want true", data)
+		}
+		if iter.Skip(3) {
+			t.Errorf("ArrayIter.Skip(3) of %s = true, want false", data)
+		}
+		if iter.Index() != 0 || iter.Value().String() != "0" {
+			t.Errorf("ArrayIter.Skip(3) of %s moves iter to %d %v, want 0", data, iter.Index(), iter.Value())
+		}
+		if (iter.Err() != nil) != tt.wantErr {
+			t.Errorf("ArrayIter.Err() of %s = %v, wantErr %v", data, iter.Err(), tt.wantErr)
+		}
+		if !tt.wantErr && (!iter.Next() || iter.Index() != 1 || iter.Value().String() != "1") {
+			t.Errorf("ArrayIter.Next() of %s after failed Skip = %d %v, want 1", data, iter.Index(), iter.Value())
+		}
+	}
+}
+
+func TestArrayIter_Seek_Error(t *testing.T) {
+	for _, data := range []string{`[]`, `[1,]`, `[1 2]`} {
+		iter, err := FromString(data).UnsafeArray()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if iter.Seek(0) || iter.Last() {
+			t.Errorf("ArrayIter.Seek() of %s = true, want false", data)
+		}
+	}
+}
+
+func BenchmarkArrayIter_Seek(b *testing.B) {
+	data := []byte("[" + strings.Repeat(`{"id": 1, "name": "n"},`, 9999) + `{"id": 1, "name": "n"}]`)
+	iter, _ := FromBytes(data).Array()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		iter.Seek(5000)
+	}
+}
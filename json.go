@@ -2,12 +2,12 @@ package jzon
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"runtime"
-	"strconv"
 )
 
 // Kind defines the type of JSON
@@ -81,6 +81,20 @@ type JSON struct {
 	limitHead int
 	limitTail int
 	err       error
+	dupPolicy DuplicateKeyPolicy
+	limits    Limits
+	scanMode  ScanMode
+	// depth is the nesting depth of the value being scanned
+	depth int
+	// tape is the structural index built by BuildIndex
+	tape *tape
+	// cursor is the index+1 of last node found in tape
+	cursor int
+	// builder records the structure while validBlockEnd is running
+	builder *tapeBuilder
+	// ctx is checked by scanners periodically if it is not nil
+	ctx   context.Context
+	ticks uint
 }
 
 // FromString returns an JSON from string
@@ -118,6 +132,71 @@ func FromReader(r io.Reader) (*JSON, error) {
 	return json, nil
 }
 
+// sub returns a new JSON from data which inherits the settings of json
+func (json *JSON) sub(data []byte) *JSON {
+	s := FromBytes(data)
+	s.dupPolicy = json.dupPolicy
+	s.limits = json.limits
+	s.scanMode = json.scanMode
+	s.ctx = json.ctx
+	return s
+}
+
+// Clone returns a copy of json which shares the underlying data,
+// moving the copy by Path, Index or ObjectIndex does not affect json
+func (json *JSON) Clone() *JSON {
+	c := *json
+	return &c
+}
+
+// value returns a detached view of the value represented by
+// json.data[head:tail], the offset is moved to head
+func (json *JSON) value() *JSON {
+	v := json.Clone()
+	v.offset = v.head
+	v.err = nil
+	return v
+}
+
+// validView returns a new JSON of the value represented by
+// json.data[head:tail] after checking it strictly, only whitespaces
+// are allowed after the value
+func (json *JSON) validView() (*JSON, error) {
+	tail := json.tail
+	if tail <= 0 {
+		tail = len(json.data)
+	}
+	v := json.sub(json.data[json.head:tail])
+	if err := v.CheckComplete(); err != nil {
+		json.err = err
+		return nil, err
+	}
+	return v, nil
+}
+
+// CheckComplete is like CheckValid, but it fails unless only whitespaces
+// follow the value, like "1 2" or `{"a": 1} junk`. Use it to check a
+// whole document.
+func (json *JSON) CheckComplete() error {
+	if !json.checkDocumentSize() {
+		return json.err
+	}
+	now := json.offset
+	defer func() {
+		json.offset = now
+	}()
+	end, _ := json.validValueEnd()
+	if end == -1 {
+		return json.err
+	}
+	json.offset = end
+	if _, ok := json.nextToken(); ok {
+		json.err = SyntaxError{Invalid, json.offset, json.data}
+		return json.err
+	}
+	return nil
+}
+
 // Reset resets JSON to be reused
 func (json *JSON) Reset() *JSON {
 	json.offset = 0
@@ -145,7 +224,12 @@ func (json *JSON) unlimit() {
 	json.limitTail = len(json.data)
 }
 
+// CheckValid checks the syntax of json strictly,
+// the Limits and DuplicateKeyPolicy are honored
 func (json *JSON) CheckValid() error {
+	if !json.checkDocumentSize() {
+		return json.err
+	}
 	end, _ := json.validValueEnd()
 	if end == -1 {
 		return json.err
@@ -164,6 +248,14 @@ func (json *JSON) nextToken() (byte, bool) {
 	if json.offset >= json.limitTail {
 		return 0, false
 	}
+	if json.scanMode == ScanSWAR {
+		if i := swarSkipSpace(json.data, json.offset, len(json.data)); i < len(json.data) {
+			json.offset = i
+			return json.data[i], true
+		}
+		json.offset = json.limitTail
+		return 0, false
+	}
 	for i, c := range json.data[json.offset:] {
 		switch c {
 		case ' ', '\n', '\t', '\r':
@@ -258,12 +350,19 @@ func (json *JSON) validStringEnd() int {
 	}
 
 	n := json.limitTail
+	swar := json.scanMode == ScanSWAR
 
 	validEnd := func() int {
 		data := json.data
 		// https://tools.ietf.org/html/rfc7159#section-7
 		// escaped := false
 		for i := json.offset; i < n; {
+			if swar {
+				// skip the bytes which need no check
+				if i = swarStringSpecial(data, i, n); i == n {
+					break
+				}
+			}
 			switch c := data[i]; {
 			case c == '\\':
 				// look one more byte
@@ -304,10 +403,13 @@ func (json *JSON) validStringEnd() int {
 	json.offset++
 	end := validEnd()
 	if end < 0 {
-		json.err = SyntaxError{String, json.offset + 1 - (end + 1), json.data}
+		json.err = SyntaxError{String, -(end + 1), json.data}
 		return -1
 	}
 	json.offset--
+	if !json.checkStringLength(json.offset, end) {
+		return -1
+	}
 	return end
 }
 
@@ -372,6 +474,13 @@ func (json *JSON) validLiteralValueEnd() int {
 }
 
 func (json *JSON) validNumberEnd() int {
+	end, _ := json.validNumberEndFlag()
+	return end
+}
+
+// validNumberEndFlag is like validNumberEnd,
+// it also returns the flag describing the number
+func (json *JSON) validNumberEndFlag() (int, flag) {
 	if json.limitTail == 0 {
 		json.limitTail = len(json.data)
 	}
@@ -489,148 +598,188 @@ func (json *JSON) validNumberEnd() int {
 		return i, flag
 	}
 
-	end, _ := validEnd()
+	end, flag := validEnd()
 
 	if end < 0 {
 		json.err = SyntaxError{Number, -end, json.data}
-		return -1
+		return -1, flag
 	}
 
-	return end
+	return end, flag
 }
 
 func (json *JSON) validArrayEnd() int {
-	validEnd := func() int {
-		flag := flagNeedStart
-		for {
-			c, ok := json.nextToken()
-			if !ok {
-				return -json.offset - 1
-			}
-			switch c {
-
-			case ',':
-				if !contains(flag, flagNeedComma) {
-					return -json.offset - 1
-				}
-				flag = remove(flag, flagNeedComma, flagNeedEnd)
-				flag = add(flag, flagNeedValue)
-				json.offset++
-			case ']':
-				if !contains(flag, flagNeedEnd) {
-					return -json.offset - 1
-				}
-				json.offset++
-				return json.offset
-			case '[':
-				if contains(flag, flagNeedValue) {
-					// [[1,2], [3,4]]
-					goto DefaultCase
-				}
+	if c, ok := json.nextToken(); !ok || c != '[' {
+		json.err = SyntaxError{Array, json.offset, json.data}
+		return -1
+	}
+	return json.validBlockEnd()
+}
 
-				if !contains(flag, flagNeedStart) {
-					return -json.offset - 1
-				}
-				flag = remove(flag, flagNeedStart)
-				flag = add(flag, flagNeedValue, flagNeedEnd)
-				json.offset++
-				break
-			DefaultCase: // label
-				fallthrough
-			default:
-				if !contains(flag, flagNeedValue) {
-					return -json.offset - 1
-				}
-				end, _ := json.validValueEnd()
-				if end == -1 {
-					return -1
-				}
-				flag = remove(flag, flagNeedValue)
-				flag = add(flag, flagNeedComma, flagNeedEnd)
-				json.offset = end
-			}
-		}
+func (json *JSON) validObjectEnd() int {
+	if c, ok := json.nextToken(); !ok || c != '{' {
+		json.err = SyntaxError{Object, json.offset, json.data}
+		return -1
 	}
+	return json.validBlockEnd()
+}
+
+// validFrame is the state of an object or array
+// which is being checked by validBlockEnd
+type validFrame struct {
+	kind Kind
+	flag flag
+	// count is the number of members or elements
+	count int
+}
 
+// validBlockEnd returns the end of the object or array at json.offset.
+// It checks syntax strictly like validValueEnd, but nested objects and
+// arrays are tracked by an explicit stack instead of recursion,
+// so deeply nested input can not exhaust the goroutine stack.
+func (json *JSON) validBlockEnd() int {
 	now := json.offset
-	end := validEnd()
-	if end < 0 {
-		if json.err == nil {
-			json.err = SyntaxError{Array, -(end + 1), json.data}
-		}
-		end = -1
+	defer func() {
+		json.offset = now
+	}()
+
+	stack := make([]validFrame, 0, 64)
+	// seen records the keys of every object in stack
+	// when duplicate keys are rejected
+	var seen []map[string]int
+
+	c, _ := json.nextToken()
+	stack, seen, ok := json.pushFrame(stack, seen, c)
+	if !ok {
+		return -1
 	}
 
-	json.offset = now
-	return end
-}
+	for {
+		if !json.checkContext() {
+			return -1
+		}
+		top := &stack[len(stack)-1]
+		c, ok := json.nextToken()
+		if !ok {
+			json.err = SyntaxError{top.kind, json.offset, json.data}
+			return -1
+		}
 
-func (json *JSON) validObjectEnd() int {
-	validEnd := func() int {
-		flag := flagNeedStart
-		for {
-			c, ok := json.nextToken()
-			if !ok {
-				return -json.offset - 1
-			}
-			switch c {
-			case '{':
-				if !contains(flag, flagNeedStart) {
-					return -json.offset - 1
-				}
-				flag = remove(flag, flagNeedStart)
-				flag = add(flag, flagNeedKey, flagNeedEnd)
-				json.offset++
-			case '"':
-				if !contains(flag, flagNeedKey) {
-					return -json.offset - 1
+		needValue := false
+		if top.kind == Object {
+			switch {
+			case c == '"' && contains(top.flag, flagNeedKey):
+				top.count++
+				if !json.checkMembers(top.count, json.offset) {
+					return -1
 				}
 				end := json.validStringEnd()
 				if end == -1 {
 					return -1
 				}
-				flag = remove(flag, flagNeedKey, flagNeedEnd)
-				flag = add(flag, flagNeedColon)
-				json.offset = end // move to end
-			case ':':
-				if !contains(flag, flagNeedColon) {
-					return -json.offset - 1
+				if json.builder != nil {
+					json.builder.key(json.offset, end)
 				}
-				json.offset++
-				end, _ := json.validValueEnd()
-				if end == -1 {
-					return -1
+				if seen != nil {
+					keys := seen[len(seen)-1]
+					key, _ := unquote(json.data[json.offset:end])
+					if first, ok := keys[key]; ok {
+						json.err = DuplicateKeyError{key, first, json.offset}
+						return -1
+					}
+					keys[key] = json.offset
 				}
-				flag = remove(flag, flagNeedColon)
-				flag = add(flag, flagNeedComma, flagNeedEnd) // clean flagColon
+				top.flag = flagNeedColon
 				json.offset = end
-			case ',':
-				if !contains(flag, flagNeedComma) {
-					return -json.offset - 1
-				}
-				flag = remove(flag, flagNeedComma, flagNeedEnd)
-				flag = add(flag, flagNeedKey)
+			case c == ':' && contains(top.flag, flagNeedColon):
+				top.flag = flagNeedComma | flagNeedEnd
 				json.offset++
-			case '}':
-				if !contains(flag, flagNeedEnd) {
-					return -json.offset - 1
+				needValue = true
+			case c == ',' && contains(top.flag, flagNeedComma):
+				top.flag = flagNeedKey
+				json.offset++
+			case c == '}' && contains(top.flag, flagNeedEnd):
+				json.offset++
+				if json.builder != nil {
+					json.builder.pop(json.offset)
 				}
+				stack = stack[:len(stack)-1]
+				if seen != nil {
+					seen = seen[:len(seen)-1]
+				}
+			default:
+				json.err = SyntaxError{Object, json.offset, json.data}
+				return -1
+			}
+		} else {
+			switch {
+			case c == ',' && contains(top.flag, flagNeedComma):
+				top.flag = flagNeedValue
 				json.offset++
-				return json.offset
+			case c == ']' && contains(top.flag, flagNeedEnd):
+				json.offset++
+				if json.builder != nil {
+					json.builder.pop(json.offset)
+				}
+				stack = stack[:len(stack)-1]
+			case c != ',' && c != ']' && contains(top.flag, flagNeedValue):
+				top.count++
+				if !json.checkElements(top.count, json.offset) {
+					return -1
+				}
+				top.flag = flagNeedComma | flagNeedEnd
+				needValue = true
+			default:
+				json.err = SyntaxError{Array, json.offset, json.data}
+				return -1
+			}
+		}
+
+		if needValue {
+			// objects and arrays are pushed into stack,
+			// other values are checked directly
+			c, ok := json.nextToken()
+			if ok && (c == '{' || c == '[') {
+				if stack, seen, ok = json.pushFrame(stack, seen, c); !ok {
+					return -1
+				}
+				continue
 			}
+			end, kind := json.validValueEnd()
+			if end == -1 {
+				return -1
+			}
+			if json.builder != nil {
+				json.builder.value(kind, json.offset, end)
+			}
+			json.offset = end
+		}
 
+		if len(stack) == 0 {
+			return json.offset
 		}
 	}
-	now := json.offset
-	end := validEnd()
-	if end < 0 {
-		if json.err == nil {
-			json.err = SyntaxError{Object, -(end + 1), json.data}
+}
+
+// pushFrame starts a new object or array at json.offset
+func (json *JSON) pushFrame(stack []validFrame, seen []map[string]int, c byte) ([]validFrame, []map[string]int, bool) {
+	frame := validFrame{kind: Array, flag: flagNeedValue | flagNeedEnd}
+	if c == '{' {
+		frame.kind = Object
+		frame.flag = flagNeedKey | flagNeedEnd
+		if json.dupPolicy == DuplicateKeyReject {
+			seen = append(seen, make(map[string]int))
 		}
-		end = -1
 	}
-	json.offset = now
-	return end
+	stack = append(stack, frame)
+	if !json.checkDepth(json.depth+len(stack), json.offset) {
+		return stack, seen, false
+	}
+	if json.builder != nil {
+		json.builder.push(frame.kind, json.offset)
+	}
+	json.offset++
+	return stack, seen, true
 }
 
 // unsafeBlockEnd finds end of the data structure, array or object.
@@ -647,8 +796,28 @@ func (json *JSON) unsafeBlockEnd(left, right byte) int {
 
 	n := json.limitTail
 
+	var counter *blockCounter
+	if json.limits.MaxDepth > 0 || json.limits.MaxObjectMembers > 0 || json.limits.MaxArrayElements > 0 {
+		counter = &blockCounter{}
+	}
+
+	// the limits need to count more structural characters,
+	// so only skip bytes without them
+	swar := json.scanMode == ScanSWAR && counter == nil
+
 	level := 0
 	for ; json.offset < n; json.offset++ {
+		if !json.checkContext() {
+			return -1
+		}
+		if swar {
+			if json.offset = swarFind(json.data, json.offset, n, left, right, '"'); json.offset == n {
+				break
+			}
+		}
+		if counter != nil && !json.countBlock(counter, json.data[json.offset]) {
+			return -1
+		}
 		switch json.data[json.offset] {
 		case left:
 			level++
@@ -716,12 +885,32 @@ func (json *JSON) unsafeNumberEnd() int {
 // ObjectIndex finds value index i by object key, then move offset to i,
 // if not found, return error
 // if occur syntax error, return error
+// if the key is duplicated, the result depends on the DuplicateKeyPolicy
 func (json *JSON) ObjectIndex(key string) error {
 	json.mustBe(Object)
+	if !json.checkDocumentSize() {
+		return json.err
+	}
+	if n, ok := json.node(); ok {
+		return json.tapeObjectIndex(n, key)
+	}
+	// the whole object must be scanned to reject duplicate keys
+	// or to find the last matched key
+	scanAll := json.dupPolicy == DuplicateKeyReject || json.dupPolicy == DuplicateKeyLastWins
+	var seen map[string]int
+	if json.dupPolicy == DuplicateKeyReject {
+		seen = make(map[string]int)
+	}
 	validIndex := func() int {
 		match := false
+		found := false
+		head, tail := 0, 0
+		members := 0
 		flag := flagNeedStart
 		for {
+			if !json.checkContext() {
+				return -1
+			}
 			c, ok := json.nextToken()
 			if !ok {
 				return -json.offset - 1
@@ -733,21 +922,31 @@ func (json *JSON) ObjectIndex(key string) error {
 				}
 				flag = remove(flag, flagNeedStart)
 				flag = add(flag, flagNeedKey, flagNeedEnd)
+				if !json.checkDepth(json.depth, json.offset) {
+					return -1
+				}
 				json.offset++
 			case '"':
 				if !contains(flag, flagNeedKey) {
 					return -json.offset - 1
 				}
+				members++
+				if !json.checkMembers(members, json.offset) {
+					return -1
+				}
 				end := json.validStringEnd()
 				if end == -1 {
 					return -1
 				}
-				s, _ := unquote(json.data[json.offset:end])
-				if k := string(s); k == key {
-					match = true
-				} else {
-					match = false
+				if seen != nil {
+					k, _ := unquote(json.data[json.offset:end])
+					if first, ok := seen[k]; ok {
+						json.err = DuplicateKeyError{k, first, json.offset}
+						return -1
+					}
+					seen[k] = json.offset
 				}
+				match = unquoteEqual(json.data[json.offset:end], key)
 
 				flag = remove(flag, flagNeedKey, flagNeedEnd)
 				flag = add(flag, flagNeedColon)
@@ -769,9 +968,13 @@ func (json *JSON) ObjectIndex(key string) error {
 				}
 
 				if match {
-					json.head = json.offset
-					json.tail = end
-					return json.offset
+					if !scanAll {
+						json.head = json.offset
+						json.tail = end
+						return json.offset
+					}
+					found = true
+					head, tail = json.offset, end
 				}
 
 				flag = remove(flag, flagNeedColon)
@@ -789,14 +992,24 @@ func (json *JSON) ObjectIndex(key string) error {
 					return -json.offset - 1
 				}
 				json.offset++
+				if found {
+					json.head = head
+					json.tail = tail
+					json.offset = head
+					return json.offset
+				}
 				json.err = fmt.Errorf("object: key[%s] not found", key)
 				return -json.offset
+			default:
+				// an unexpected token like the value without colon
+				return -json.offset - 1
 			}
-
 		}
 	}
 
+	json.depth++
 	end := validIndex()
+	json.depth--
 	if end < 0 && json.err == nil {
 		json.err = SyntaxError{Object, -(end + 1), json.data}
 	}
@@ -809,10 +1022,19 @@ func (json *JSON) ObjectIndex(key string) error {
 // if occur syntax error, return error
 func (json *JSON) Index(index int) error {
 	json.mustBe(Array)
+	if !json.checkDocumentSize() {
+		return json.err
+	}
+	if n, ok := json.node(); ok {
+		return json.tapeIndex(n, index)
+	}
 	validIndex := func() int {
 		flag := flagNeedStart
 		i := 0
 		for {
+			if !json.checkContext() {
+				return -1
+			}
 			c, ok := json.nextToken()
 			if !ok {
 				return -json.offset - 1
@@ -842,6 +1064,9 @@ func (json *JSON) Index(index int) error {
 				}
 				flag = remove(flag, flagNeedStart)
 				flag = add(flag, flagNeedValue, flagNeedEnd)
+				if !json.checkDepth(json.depth, json.offset) {
+					return -1
+				}
 				json.offset++
 				break
 			DefaultCase: // label
@@ -850,6 +1075,9 @@ func (json *JSON) Index(index int) error {
 				if !contains(flag, flagNeedValue) {
 					return -json.offset - 1
 				}
+				if !json.checkElements(i+1, json.offset) {
+					return -1
+				}
 				var end int
 				if i == index {
 					end, _ = json.validValueEnd()
@@ -875,7 +1103,9 @@ func (json *JSON) Index(index int) error {
 		}
 	}
 
+	json.depth++
 	end := validIndex()
+	json.depth--
 	if end < 0 && json.err == nil {
 		json.err = SyntaxError{Object, -(end + 1), json.data}
 	}
@@ -889,6 +1119,11 @@ func (json *JSON) Path(keys ...interface{}) error {
 		return nil
 	}
 
+	// every key moves into a deeper level
+	defer func(depth int) {
+		json.depth = depth
+	}(json.depth)
+
 	for _, key := range keys {
 		kind := json.Predict()
 
@@ -915,55 +1150,90 @@ func (json *JSON) Path(keys ...interface{}) error {
 			json.err = fmt.Errorf("%v is not string or int", keys[0])
 			return json.err
 		}
+		json.depth++
 	}
 	return nil
 }
 
-// ParseInt64 parses an int Number json value to int64
+// ParseInt64 parses an int Number json value to int64,
+// number in float or scientific notation is accepted
+// if it is an integer, like 1e3 or 10.0
 func (json *JSON) ParseInt64() (int64, error) {
+	b, f, err := json.number("ParseInt64", "int")
+	if err != nil {
+		return 0, err
+	}
+	if !contains(f, flagIsFloat) && !contains(f, flagIsScientific) {
+		return parseIntBytes("ParseInt64", b)
+	}
+	u, neg, err := parseIntegral("ParseInt64", b)
+	if err != nil {
+		return 0, err
+	}
+	return toInt64("ParseInt64", b, u, neg)
+}
+
+// ParseFloat parses an float Number json value to float64
+func (json *JSON) ParseFloat() (float64, error) {
+	b, f, err := json.number("ParseFloat", "float")
+	if err != nil {
+		return 0, err
+	}
+	return parseFloatBytes(b, f)
+}
+
+// ParseString parses an String json value to float64
+func (json *JSON) ParseString() (string, error) {
 	if json.tail <= 0 {
 		json.tail = len(json.data)
 	}
-
 	json.offset = json.head
 	kind := json.Predict()
-	if kind != Number {
-		return 0, fmt.Errorf("ParseInt64: Can not parse %s JSON to int", kind)
+	if kind != String {
+		return "", fmt.Errorf("ParseString: Can not parse %s JSON to string", kind)
 	}
-
-	return strconv.ParseInt(string(json.data[json.head:json.tail]), 10, 64)
+	s, ok := unquote(json.data[json.head:json.tail])
+	if !ok {
+		return "", errors.New("ParseString: unquote string error")
+	}
+	return s, nil
 }
 
-// ParseFloat parses an float Number json value to float64
-func (json *JSON) ParseFloat() (float64, error) {
+// ParseBytes parses an String json value to byte slice without allocation
+// if possible. If the string contains no escapes, the returned slice refers
+// to the underlying data and must not be modified, otherwise the string is
+// decoded into buf[:0].
+func (json *JSON) ParseBytes(buf []byte) ([]byte, error) {
 	if json.tail <= 0 {
 		json.tail = len(json.data)
 	}
-
 	json.offset = json.head
 	kind := json.Predict()
-	if kind != Number {
-		return 0, fmt.Errorf("ParseFloat: Can not parse %s JSON to float", kind)
+	if kind != String {
+		return nil, fmt.Errorf("ParseBytes: Can not parse %s JSON to bytes", kind)
 	}
-
-	return strconv.ParseFloat(string(json.data[json.head:json.tail]), 64)
+	b, ok := unquoteInto(buf, json.data[json.offset:json.tail])
+	if !ok {
+		return nil, errors.New("ParseBytes: unquote string error")
+	}
+	return b, nil
 }
 
-// ParseString parses an String json value to float64
-func (json *JSON) ParseString() (string, error) {
+// AppendString appends the unquoted String json value to dst
+func (json *JSON) AppendString(dst []byte) ([]byte, error) {
 	if json.tail <= 0 {
 		json.tail = len(json.data)
 	}
 	json.offset = json.head
 	kind := json.Predict()
 	if kind != String {
-		return "", fmt.Errorf("ParseString: Can not parse %s JSON to string", kind)
+		return dst, fmt.Errorf("AppendString: Can not parse %s JSON to string", kind)
 	}
-	s, ok := unquote(json.data[json.head:json.tail])
+	b, ok := appendUnquote(dst, json.data[json.offset:json.tail])
 	if !ok {
-		return "", errors.New("ParseString: unquote string error")
+		return dst, errors.New("AppendString: unquote string error")
 	}
-	return s, nil
+	return b, nil
 }
 
 // ParseBoolean parses an Bool json value to bool
@@ -1038,3 +1308,11 @@ func isDigit(c byte) bool {
 	}
 	return false
 }
+
+func isSpace(c byte) bool {
+	switch c {
+	case ' ', '\n', '\t', '\r':
+		return true
+	}
+	return false
+}
@@ -431,8 +431,15 @@ func TestJSON_ParseInt64(t *testing.T) {
 		wantErr bool
 	}{
 		{"1", fields{[]byte(`123`)}, 123, false},
-		{"2", fields{[]byte(`1.23e2`)}, 0, true},
+		{"2", fields{[]byte(`1.23e2`)}, 123, false},
 		{"3", fields{[]byte(`123e`)}, 0, true},
+		{"4", fields{[]byte(`1.234e2`)}, 0, true},
+		{"5", fields{[]byte(`10.0`)}, 10, false},
+		{"6", fields{[]byte(`-1e3`)}, -1000, false},
+		{"7", fields{[]byte(`9223372036854775807`)}, 9223372036854775807, false},
+		{"8", fields{[]byte(`9.223372036854775808e18`)}, 0, true},
+		{"9", fields{[]byte(`-9.223372036854775808e18`)}, -9223372036854775808, false},
+		{"10", fields{[]byte(`"123"`)}, 0, true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -534,3 +541,117 @@ func TestJSON_ParseString(t *testing.T) {
 		})
 	}
 }
+
+func TestJSON_ObjectIndex_DuplicateKey(t *testing.T) {
+	data := `{"k": 1, "o": {}, "k": 2}`
+	tests := []struct {
+		name    string
+		policy  DuplicateKeyPolicy
+		want    string
+		wantErr bool
+	}{
+		{"allow", DuplicateKeyAllow, "1", false},
+		{"reject", DuplicateKeyReject, "", true},
+		{"first", DuplicateKeyFirstWins, "1", false},
+		{"last", DuplicateKeyLastWins, "2", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			json := FromString(data).SetDuplicateKeyPolicy(tt.policy)
+			err := json.ObjectIndex("k")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("JSON.ObjectIndex() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				dup, ok := err.(DuplicateKeyError)
+				if !ok || dup.Key != "k" || dup.First != 1 || dup.Second != 18 {
+					t.Errorf("JSON.ObjectIndex() error = %#v, want DuplicateKeyError{k, 1, 18}", err)
+				}
+				return
+			}
+			if got := json.String(); got != tt.want {
+				t.Errorf("JSON.ObjectIndex() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSON_CheckValid_DuplicateKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		policy  DuplicateKeyPolicy
+		wantErr bool
+	}{
+		{"1", `{"k": 1, "k": 2}`, DuplicateKeyAllow, false},
+		{"2", `{"k": 1, "k": 2}`, DuplicateKeyReject, true},
+		{"3", `{"k": 1, "K": 2}`, DuplicateKeyReject, false},
+		{"4", `[{"k": 1}, {"k": 2}]`, DuplicateKeyReject, false},
+		{"5", `[{"o": {"k": 1, "k": 2}}]`, DuplicateKeyReject, true},
+		{"6", `{"k": 1, "k": 2}`, DuplicateKeyLastWins, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			json := FromString(tt.data).SetDuplicateKeyPolicy(tt.policy)
+			if err := json.CheckValid(); (err != nil) != tt.wantErr {
+				t.Errorf("JSON.CheckValid() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestJSON_ParseBytes(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    string
+		wantRaw bool
+		wantErr bool
+	}{
+		{"1", `"test"`, "test", true, false},
+		{"2", `"test\ttest"`, "test\ttest", false, false},
+		{"3", `123`, "", false, true},
+		{"4", `"\x"`, "", false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			json := FromString(tt.data)
+			buf := make([]byte, 0, 32)
+			got, err := json.ParseBytes(buf)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("JSON.ParseBytes() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if string(got) != tt.want {
+				t.Errorf("JSON.ParseBytes() = %q, want %q", got, tt.want)
+			}
+			if err == nil {
+				raw := &got[0] == &json.data[1]
+				if raw != tt.wantRaw {
+					t.Errorf("JSON.ParseBytes() refers to data = %v, want %v", raw, tt.wantRaw)
+				}
+			}
+
+			appended, err := json.AppendString([]byte("s:"))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("JSON.AppendString() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && string(appended) != "s:"+tt.want {
+				t.Errorf("JSON.AppendString() = %q, want %q", appended, "s:"+tt.want)
+			}
+		})
+	}
+}
+
+func TestJSON_ObjectIndex_EscapedKey(t *testing.T) {
+	json := FromString(`{"abc": 1, "d\te": 2}`)
+	if err := json.ObjectIndex("abc"); err != nil || json.String() != "1" {
+		t.Errorf("JSON.ObjectIndex() = %v, %v, want 1, nil", json, err)
+	}
+	json = FromString(`{"abc": 1, "d\te": 2}`)
+	if err := json.ObjectIndex("d\te"); err != nil || json.String() != "2" {
+		t.Errorf("JSON.ObjectIndex() = %v, %v, want 2, nil", json, err)
+	}
+}
@@ -0,0 +1,129 @@
+package jzon
+
+import (
+	"io"
+	"io/ioutil"
+)
+
+// Limits defines the resource limits applied when parsing untrusted JSON.
+// A zero value field means no limit.
+type Limits struct {
+	// MaxDepth is the maximum nesting depth of objects and arrays,
+	// the depth of a top level object or array is 1
+	MaxDepth int
+	// MaxDocumentSize is the maximum size of document in bytes
+	MaxDocumentSize int
+	// MaxStringLength is the maximum length of a string (key or value)
+	// in bytes, the quotes are not counted
+	MaxStringLength int
+	// MaxObjectMembers is the maximum number of members in an object
+	MaxObjectMembers int
+	// MaxArrayElements is the maximum number of elements in an array
+	MaxArrayElements int
+}
+
+// SetLimits sets the resource limits honored by all the scanners,
+// and inherited by the iterators created from json.
+func (json *JSON) SetLimits(limits Limits) *JSON {
+	json.limits = limits
+	return json
+}
+
+// FromReaderWithLimits is like FromReader, but it stops reading and returns
+// a LimitError once the document exceeds limits.MaxDocumentSize.
+// The limits are set to the returned JSON.
+func FromReaderWithLimits(r io.Reader, limits Limits) (*JSON, error) {
+	if limits.MaxDocumentSize > 0 {
+		// read one more byte to find out whether the document is too large
+		r = io.LimitReader(r, int64(limits.MaxDocumentSize)+1)
+	}
+	buf, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if limits.MaxDocumentSize > 0 && len(buf) > limits.MaxDocumentSize {
+		return nil, LimitError{"MaxDocumentSize", limits.MaxDocumentSize, limits.MaxDocumentSize}
+	}
+	return FromBytes(buf).SetLimits(limits), nil
+}
+
+// checkDocumentSize checks the size of json.data,
+// it sets json.err and returns false if the limit is exceeded
+func (json *JSON) checkDocumentSize() bool {
+	if max := json.limits.MaxDocumentSize; max > 0 && len(json.data) > max {
+		json.err = LimitError{"MaxDocumentSize", max, max}
+		return false
+	}
+	return true
+}
+
+// checkDepth checks the nesting depth at offset
+func (json *JSON) checkDepth(depth, offset int) bool {
+	if max := json.limits.MaxDepth; max > 0 && depth > max {
+		json.err = LimitError{"MaxDepth", max, offset}
+		return false
+	}
+	return true
+}
+
+// checkStringLength checks the length of string json.data[head:tail]
+func (json *JSON) checkStringLength(head, tail int) bool {
+	if max := json.limits.MaxStringLength; max > 0 && tail-head-2 > max {
+		json.err = LimitError{"MaxStringLength", max, head}
+		return false
+	}
+	return true
+}
+
+// checkMembers checks the number of members of an object
+func (json *JSON) checkMembers(n, offset int) bool {
+	if max := json.limits.MaxObjectMembers; max > 0 && n > max {
+		json.err = LimitError{"MaxObjectMembers", max, offset}
+		return false
+	}
+	return true
+}
+
+// checkElements checks the number of elements of an array
+func (json *JSON) checkElements(n, offset int) bool {
+	if max := json.limits.MaxArrayElements; max > 0 && n > max {
+		json.err = LimitError{"MaxArrayElements", max, offset}
+		return false
+	}
+	return true
+}
+
+// blockCounter counts the nesting depth and the size of blocks
+// for unsafeBlockEnd when limits are set
+type blockCounter struct {
+	blocks []byte
+	commas []int
+}
+
+// countBlock counts the token c into counter,
+// it returns false if any limit is exceeded
+func (json *JSON) countBlock(counter *blockCounter, c byte) bool {
+	switch c {
+	case '{', '[':
+		counter.blocks = append(counter.blocks, c)
+		counter.commas = append(counter.commas, 0)
+		return json.checkDepth(json.depth+len(counter.blocks), json.offset)
+	case '}', ']':
+		if n := len(counter.blocks); n > 0 {
+			counter.blocks = counter.blocks[:n-1]
+			counter.commas = counter.commas[:n-1]
+		}
+	case ',':
+		n := len(counter.blocks)
+		if n == 0 {
+			return true
+		}
+		counter.commas[n-1]++
+		// there is one more value than commas
+		if counter.blocks[n-1] == '{' {
+			return json.checkMembers(counter.commas[n-1]+1, json.offset)
+		}
+		return json.checkElements(counter.commas[n-1]+1, json.offset)
+	}
+	return true
+}
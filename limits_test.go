@@ -0,0 +1,133 @@
+package jzon
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestJSON_CheckValid_Limits(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      string
+		limits    Limits
+		wantLimit string
+	}{
+		{"1", `[[[1]]]`, Limits{MaxDepth: 3}, ""},
+		{"2", `[[[[1]]]]`, Limits{MaxDepth: 3}, "MaxDepth"},
+		{"3", `{"a":{"b":[{}]}}`, Limits{MaxDepth: 3}, "MaxDepth"},
+		{"4", `"abcd"`, Limits{MaxStringLength: 4}, ""},
+		{"5", `{"abcde": 1}`, Limits{MaxStringLength: 4}, "MaxStringLength"},
+		{"6", `{"a":1,"b":2}`, Limits{MaxObjectMembers: 2}, ""},
+		{"7", `{"a":1,"b":2,"c":3}`, Limits{MaxObjectMembers: 2}, "MaxObjectMembers"},
+		{"8", `[1,2]`, Limits{MaxArrayElements: 2}, ""},
+		{"9", `[[1,2,3]]`, Limits{MaxArrayElements: 2}, "MaxArrayElements"},
+		{"10", `[1,2]`, Limits{MaxDocumentSize: 5}, ""},
+		{"11", `[1, 2]`, Limits{MaxDocumentSize: 5}, "MaxDocumentSize"},
+		{"12", strings.Repeat("[", 1000000), Limits{MaxDepth: 100}, "MaxDepth"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := FromString(tt.data).SetLimits(tt.limits).CheckValid()
+			checkLimitError(t, "JSON.CheckValid()", err, tt.wantLimit)
+		})
+	}
+}
+
+func TestJSON_unsafeValueEnd_Limits(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      string
+		limits    Limits
+		wantLimit string
+	}{
+		{"1", `[{"a":[1]}]`, Limits{MaxDepth: 3}, ""},
+		{"2", `[{"a":[[1]]}]`, Limits{MaxDepth: 3}, "MaxDepth"},
+		{"3", `[{"a":1,"b":"x,y,z"}]`, Limits{MaxObjectMembers: 2}, ""},
+		{"4", `[{"a":1,"b":2,"c":3}]`, Limits{MaxObjectMembers: 2}, "MaxObjectMembers"},
+		{"5", `{"a":[1,2],"b":[3]}`, Limits{MaxArrayElements: 2}, ""},
+		{"6", `{"a":[1,2,3]}`, Limits{MaxArrayElements: 2}, "MaxArrayElements"},
+		{"7", `["abcde"]`, Limits{MaxStringLength: 4}, "MaxStringLength"},
+		{"8", strings.Repeat("[", 1000000), Limits{MaxDepth: 100}, "MaxDepth"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			json := FromString(tt.data).SetLimits(tt.limits)
+			var err error
+			if end, _ := json.unsafeValueEnd(); end == -1 {
+				err = json.err
+			}
+			checkLimitError(t, "JSON.unsafeValueEnd()", err, tt.wantLimit)
+		})
+	}
+}
+
+func TestJSON_Path_Limits(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      string
+		limits    Limits
+		keys      []interface{}
+		wantLimit string
+	}{
+		{"1", `{"a":[1,2,3]}`, Limits{MaxArrayElements: 3}, []interface{}{"a", 2}, ""},
+		{"2", `{"a":[1,2,3,4]}`, Limits{MaxArrayElements: 3}, []interface{}{"a", 3}, "MaxArrayElements"},
+		{"3", `{"a":1,"b":2,"c":3}`, Limits{MaxObjectMembers: 2}, []interface{}{"c"}, "MaxObjectMembers"},
+		{"4", `{"a":[[[1]]]}`, Limits{MaxDepth: 3}, []interface{}{"a"}, "MaxDepth"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := FromString(tt.data).SetLimits(tt.limits).Path(tt.keys...)
+			checkLimitError(t, "JSON.Path()", err, tt.wantLimit)
+		})
+	}
+}
+
+func TestJSON_Object_Limits(t *testing.T) {
+	json := FromString(`{"a":1,"b":2,"c":3}`).SetLimits(Limits{MaxObjectMembers: 2})
+	_, err := json.Object()
+	checkLimitError(t, "JSON.Object()", err, "MaxObjectMembers")
+
+	json = FromString(`[1,2,3]`).SetLimits(Limits{MaxArrayElements: 2})
+	_, err = json.Array()
+	checkLimitError(t, "JSON.Array()", err, "MaxArrayElements")
+}
+
+func TestFromReaderWithLimits(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      string
+		limits    Limits
+		wantLimit string
+	}{
+		{"1", `[1,2]`, Limits{MaxDocumentSize: 5}, ""},
+		{"2", `[1, 2]`, Limits{MaxDocumentSize: 5}, "MaxDocumentSize"},
+		{"3", `[1, 2]`, Limits{}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			json, err := FromReaderWithLimits(strings.NewReader(tt.data), tt.limits)
+			checkLimitError(t, "FromReaderWithLimits()", err, tt.wantLimit)
+			if err == nil && json.String() != tt.data {
+				t.Errorf("FromReaderWithLimits() = %v, want %v", json, tt.data)
+			}
+		})
+	}
+}
+
+func checkLimitError(t *testing.T, method string, err error, wantLimit string) {
+	t.Helper()
+	if wantLimit == "" {
+		if err != nil {
+			t.Errorf("%s error = %v, want nil", method, err)
+		}
+		return
+	}
+	le, ok := err.(LimitError)
+	if !ok {
+		t.Errorf("%s error = %v, want LimitError", method, err)
+		return
+	}
+	if le.Limit != wantLimit {
+		t.Errorf("%s error = %v, want limit %v", method, err, wantLimit)
+	}
+}
@@ -0,0 +1,54 @@
+package jzon
+
+import (
+	"bytes"
+	stdjson "encoding/json"
+	"errors"
+)
+
+// FromRawMessage returns a JSON from json.RawMessage of encoding/json,
+// the data is not copied
+func FromRawMessage(m stdjson.RawMessage) *JSON {
+	return FromBytes(m)
+}
+
+// RawMessage returns the json value as json.RawMessage of encoding/json,
+// the whitespaces around value are trimmed. It refers to the underlying
+// data without copying, so it must not be modified.
+func (json *JSON) RawMessage() stdjson.RawMessage {
+	tail := json.tail
+	if tail <= 0 {
+		tail = len(json.data)
+	}
+	return stdjson.RawMessage(bytes.TrimSpace(json.data[json.head:tail]))
+}
+
+// MarshalJSON implements json.Marshaler of encoding/json,
+// it returns the underlying slice of json value without re-encoding
+func (json *JSON) MarshalJSON() ([]byte, error) {
+	if json == nil {
+		return []byte("null"), nil
+	}
+	raw := json.RawMessage()
+	if len(raw) == 0 {
+		return nil, errors.New("jzon: can not marshal empty JSON")
+	}
+	return raw, nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler of encoding/json, data is
+// copied and checked by CheckValid, the settings of json are kept
+func (json *JSON) UnmarshalJSON(data []byte) error {
+	if json == nil {
+		return errors.New("jzon: UnmarshalJSON on nil pointer")
+	}
+	buf := make([]byte, len(data))
+	copy(buf, data)
+
+	v := json.sub(buf)
+	if err := v.CheckValid(); err != nil {
+		return err
+	}
+	*json = *v
+	return nil
+}
@@ -0,0 +1,101 @@
+package jzon
+
+import (
+	stdjson "encoding/json"
+	"testing"
+)
+
+func TestJSON_MarshalJSON(t *testing.T) {
+	type payload struct {
+		ID    int    `json:"id"`
+		Data  *JSON  `json:"data"`
+		Empty *JSON  `json:"empty"`
+		Name  string `json:"name"`
+	}
+
+	in := `{"id":1,"data":{"b": [1, 2.50, "x"], "a": null},"empty":null,"name":"n"}`
+	var p payload
+	if err := stdjson.Unmarshal([]byte(in), &p); err != nil {
+		t.Fatal(err)
+	}
+	if p.Data == nil || p.Data.String() != `{"b": [1, 2.50, "x"], "a": null}` {
+		t.Errorf("UnmarshalJSON() = %v", p.Data)
+	}
+	if p.Empty != nil {
+		t.Errorf("UnmarshalJSON() of null = %v, want nil", p.Empty)
+	}
+	if got := p.Data.GetFloat(0, "b", 1); got != 2.5 {
+		t.Errorf("JSON.GetFloat(b, 1) = %v, want 2.5", got)
+	}
+
+	out, err := stdjson.Marshal(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// encoding/json compacts the output of Marshaler
+	want := `{"id":1,"data":{"b":[1,2.50,"x"],"a":null},"empty":null,"name":"n"}`
+	if string(out) != want {
+		t.Errorf("json.Marshal() = %s, want %s", out, want)
+	}
+
+	// Marshal returns the exact underlying slice
+	json := FromString(`{"a": {"b": 1.0}}`)
+	if err := json.Path("a"); err != nil {
+		t.Fatal(err)
+	}
+	raw, err := json.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(raw) != `{"b": 1.0}` || &raw[0] != &json.data[json.head] {
+		t.Errorf("JSON.MarshalJSON() = %s, want the underlying slice", raw)
+	}
+
+	if _, err := FromString(``).MarshalJSON(); err == nil {
+		t.Errorf("JSON.MarshalJSON() of empty error = nil, want error")
+	}
+	var nilJSON *JSON
+	if raw, _ := nilJSON.MarshalJSON(); string(raw) != "null" {
+		t.Errorf("nil JSON.MarshalJSON() = %s, want null", raw)
+	}
+}
+
+func TestJSON_UnmarshalJSON(t *testing.T) {
+	data := []byte(`{"a": 1, "a": 2}`)
+
+	json := new(JSON).SetDuplicateKeyPolicy(DuplicateKeyReject)
+	if err := json.UnmarshalJSON(data); err == nil {
+		t.Errorf("JSON.UnmarshalJSON() with Reject error = nil, want error")
+	}
+
+	json = new(JSON)
+	if err := json.UnmarshalJSON(data); err != nil {
+		t.Fatal(err)
+	}
+	// data is copied
+	data[6] = '9'
+	if got := json.GetInt(0, "a"); got != 1 {
+		t.Errorf("JSON.GetInt(a) = %v, want 1", got)
+	}
+
+	if err := json.UnmarshalJSON([]byte(`[1,`)); err == nil {
+		t.Errorf("JSON.UnmarshalJSON() of invalid data error = nil, want error")
+	}
+}
+
+func TestRawMessage(t *testing.T) {
+	m := stdjson.RawMessage(`[1, 2]`)
+	json := FromRawMessage(m)
+	if got := json.GetInt(0, 1); got != 2 {
+		t.Errorf("FromRawMessage().GetInt(1) = %v, want 2", got)
+	}
+	if err := json.Index(0); err != nil {
+		t.Fatal(err)
+	}
+	if got := json.RawMessage(); string(got) != "1" {
+		t.Errorf("JSON.RawMessage() = %s, want 1", got)
+	}
+	if got := FromString(" true\n").RawMessage(); string(got) != "true" {
+		t.Errorf("JSON.RawMessage() = %s, want true", got)
+	}
+}
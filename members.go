@@ -0,0 +1,77 @@
+//go:build go1.23
+
+package jzon
+
+import "iter"
+
+// Members returns an iterator over the key and value pairs of the object
+// json value, it can be used with range:
+//
+//	for key, value := range json.Members() {
+//		// do something
+//	}
+//	if err := json.Err(); err != nil {
+//		// the object is invalid
+//	}
+//
+// The object is checked strictly before iterating. If it is invalid or
+// not an object, nothing is yielded and the error is reported by json.Err.
+// Every value is an independent view which is not changed by the
+// following iterations. The DuplicateKeyPolicy is honored.
+func (json *JSON) Members() iter.Seq2[string, *JSON] {
+	return func(yield func(string, *JSON) bool) {
+		json.err = nil
+		v, err := json.validView()
+		if err != nil {
+			return
+		}
+		if kind := v.Kind(); kind != Object {
+			json.err = &KindError{"Members", kind}
+			return
+		}
+		it, err := v.UnsafeObject()
+		if err != nil {
+			json.err = err
+			return
+		}
+		for it.Next() {
+			if !yield(it.Key(), it.Value()) {
+				return
+			}
+		}
+		json.err = it.Err()
+	}
+}
+
+// Elements returns an iterator over the index and value pairs of the
+// array json value, it can be used with range:
+//
+//	for i, value := range json.Elements() {
+//		// do something
+//	}
+//
+// The errors are reported like Members.
+func (json *JSON) Elements() iter.Seq2[int, *JSON] {
+	return func(yield func(int, *JSON) bool) {
+		json.err = nil
+		v, err := json.validView()
+		if err != nil {
+			return
+		}
+		if kind := v.Kind(); kind != Array {
+			json.err = &KindError{"Elements", kind}
+			return
+		}
+		it, err := v.UnsafeArray()
+		if err != nil {
+			json.err = err
+			return
+		}
+		for it.Next() {
+			if !yield(it.Index(), it.Value()) {
+				return
+			}
+		}
+		json.err = it.Err()
+	}
+}
@@ -0,0 +1,115 @@
+//go:build go1.23
+
+package jzon
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestJSON_Members(t *testing.T) {
+	json := FromString(`{"a": 1, "b": [2, 3], "c": {"d": "e"}}`)
+
+	var keys []string
+	var values []*JSON
+	for key, value := range json.Members() {
+		keys = append(keys, key)
+		values = append(values, value)
+	}
+	if err := json.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"a", "b", "c"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("JSON.Members() keys = %v, want %v", keys, want)
+	}
+	// values are independent views
+	want := []string{`1`, `[2, 3]`, `{"d": "e"}`}
+	for i, v := range values {
+		if v.String() != want[i] {
+			t.Errorf("JSON.Members() value[%d] = %v, want %v", i, v, want[i])
+		}
+	}
+	if got := values[2].GetString("", "d"); got != "e" {
+		t.Errorf("value.GetString(d) = %v, want e", got)
+	}
+
+	// break
+	n := 0
+	for range json.Members() {
+		n++
+		break
+	}
+	if n != 1 {
+		t.Errorf("JSON.Members() yielded %d times after break, want 1", n)
+	}
+
+	// duplicate key policy
+	json = FromString(`{"a": 1, "a": 2}`).SetDuplicateKeyPolicy(DuplicateKeyLastWins)
+	for key, value := range json.Members() {
+		if key != "a" || value.String() != "2" {
+			t.Errorf("JSON.Members() with LastWins = %v %v", key, value)
+		}
+	}
+}
+
+func TestJSON_Members_Error(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"1", `{"a": 1,}`},
+		{"2", `{"a" 1}`},
+		{"3", `[1]`},
+		{"4", ``},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			json := FromString(tt.data)
+			for key := range json.Members() {
+				t.Errorf("JSON.Members() yielded %v", key)
+			}
+			if json.Err() == nil {
+				t.Errorf("JSON.Err() = nil, want error")
+			}
+		})
+	}
+}
+
+func TestJSON_Elements(t *testing.T) {
+	json := FromString(`[1, "x", [true], {}]`)
+
+	var indexes []int
+	var values []string
+	for i, value := range json.Elements() {
+		indexes = append(indexes, i)
+		values = append(values, value.String())
+	}
+	if err := json.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{0, 1, 2, 3}; !reflect.DeepEqual(indexes, want) {
+		t.Errorf("JSON.Elements() indexes = %v, want %v", indexes, want)
+	}
+	if want := []string{`1`, `"x"`, `[true]`, `{}`}; !reflect.DeepEqual(values, want) {
+		t.Errorf("JSON.Elements() values = %v, want %v", values, want)
+	}
+
+	for i := range json.Elements() {
+		if i > 1 {
+			t.Errorf("JSON.Elements() yielded %d after break", i)
+		}
+		if i == 1 {
+			break
+		}
+	}
+
+	for _, data := range []string{`[1,]`, `[1 2]`, `{}`} {
+		json := FromString(data)
+		for i := range json.Elements() {
+			t.Errorf("JSON.Elements() of %s yielded %v", data, i)
+		}
+		if json.Err() == nil {
+			t.Errorf("JSON.Elements() of %s JSON.Err() = nil, want error", data)
+		}
+	}
+}
@@ -0,0 +1,475 @@
+package jzon
+
+import (
+	stdjson "encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// maxBigExponent is the maximum exponent accepted by ParseBigInt and
+// ParseDecimal, it prevents 1e1000000000 from allocating huge memory
+const maxBigExponent = 1 << 16
+
+// Decimal is an exact decimal number, the value is Unscaled * 10^(-Scale).
+// It is useful for money fields which can not be represented by float.
+type Decimal struct {
+	Unscaled *big.Int
+	Scale    int
+}
+
+// String returns the decimal in plain notation, like -12.30
+func (d Decimal) String() string {
+	if d.Unscaled == nil {
+		return "0"
+	}
+	s := new(big.Int).Abs(d.Unscaled).String()
+	if d.Scale > 0 {
+		if len(s) <= d.Scale {
+			s = strings.Repeat("0", d.Scale-len(s)+1) + s
+		}
+		s = s[:len(s)-d.Scale] + "." + s[len(s)-d.Scale:]
+	}
+	if d.Unscaled.Sign() < 0 {
+		s = "-" + s
+	}
+	return s
+}
+
+// Rat returns the decimal as a big.Rat
+func (d Decimal) Rat() *big.Rat {
+	r := new(big.Rat)
+	if d.Unscaled == nil {
+		return r
+	}
+	r.SetInt(d.Unscaled)
+	if d.Scale > 0 {
+		scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(d.Scale)), nil)
+		r.Quo(r, new(big.Rat).SetInt(scale))
+	}
+	return r
+}
+
+// numberParts is the parts of a JSON number, the value is
+// (-1)^neg * (intPart.fracPart) * 10^exp
+type numberParts struct {
+	neg      bool
+	intPart  []byte
+	fracPart []byte
+	exp      int
+}
+
+// splitNumber splits the valid JSON number b to parts
+func splitNumber(b []byte) numberParts {
+	var p numberParts
+	i := 0
+	if i < len(b) && b[i] == '-' {
+		p.neg = true
+		i++
+	}
+	start := i
+	for i < len(b) && isDigit(b[i]) {
+		i++
+	}
+	p.intPart = b[start:i]
+	if i < len(b) && b[i] == '.' {
+		i++
+		start = i
+		for i < len(b) && isDigit(b[i]) {
+			i++
+		}
+		p.fracPart = b[start:i]
+	}
+	if i < len(b) && (b[i] == 'e' || b[i] == 'E') {
+		i++
+		negExp := false
+		if i < len(b) && (b[i] == '-' || b[i] == '+') {
+			negExp = b[i] == '-'
+			i++
+		}
+		for ; i < len(b); i++ {
+			// clamp the exponent, it is big enough to overflow anything
+			if p.exp < math.MaxInt32/10 {
+				p.exp = p.exp*10 + int(b[i]-'0')
+			}
+		}
+		if negExp {
+			p.exp = -p.exp
+		}
+	}
+	return p
+}
+
+// len returns the number of digits
+func (p *numberParts) len() int {
+	return len(p.intPart) + len(p.fracPart)
+}
+
+// digit returns the i-th digit of intPart and fracPart
+func (p *numberParts) digit(i int) byte {
+	if i < len(p.intPart) {
+		return p.intPart[i] - '0'
+	}
+	return p.fracPart[i-len(p.intPart)] - '0'
+}
+
+// trim returns the range [lo, hi) of significant digits and the exponent,
+// the value is digits[lo:hi] * 10^exp
+func (p *numberParts) trim() (lo, hi, exp int) {
+	lo, hi = 0, p.len()
+	exp = p.exp - len(p.fracPart)
+	for lo < hi && p.digit(lo) == 0 {
+		lo++
+	}
+	for hi > lo && p.digit(hi-1) == 0 {
+		hi--
+		exp++
+	}
+	if lo == hi {
+		exp = 0
+	}
+	return
+}
+
+// digits returns the digits of p without dot
+func (p *numberParts) digits() string {
+	return string(p.intPart) + string(p.fracPart)
+}
+
+func numError(fn string, b []byte, err error) error {
+	return &strconv.NumError{Func: fn, Num: string(b), Err: err}
+}
+
+// parseIntegral parses the integer JSON number b in any notation to uint64
+// and its sign, it returns strconv.ErrSyntax if b is not an integer and
+// strconv.ErrRange if b overflows uint64
+func parseIntegral(fn string, b []byte) (uint64, bool, error) {
+	p := splitNumber(b)
+	lo, hi, exp := p.trim()
+	if exp < 0 {
+		return 0, false, numError(fn, b, strconv.ErrSyntax)
+	}
+	if hi-lo+exp > 20 {
+		return 0, false, numError(fn, b, strconv.ErrRange)
+	}
+	var u uint64
+	for i := lo; i < hi+exp; i++ {
+		var d uint64
+		if i < hi {
+			d = uint64(p.digit(i))
+		}
+		if u > (math.MaxUint64-d)/10 {
+			return 0, false, numError(fn, b, strconv.ErrRange)
+		}
+		u = u*10 + d
+	}
+	return u, p.neg, nil
+}
+
+// toInt64 converts the sign and magnitude to int64
+func toInt64(fn string, b []byte, u uint64, neg bool) (int64, error) {
+	if neg {
+		if u > 1<<63 {
+			return 0, numError(fn, b, strconv.ErrRange)
+		}
+		return -int64(u), nil
+	}
+	if u > math.MaxInt64 {
+		return 0, numError(fn, b, strconv.ErrRange)
+	}
+	return int64(u), nil
+}
+
+// number returns the bytes and flag of the Number json value
+// represented by json.data[head:tail]
+func (json *JSON) number(method, to string) ([]byte, flag, error) {
+	if json.tail <= 0 {
+		json.tail = len(json.data)
+	}
+	json.offset = json.head
+	kind := json.Predict()
+	if kind != Number {
+		return nil, 0, fmt.Errorf("%s: Can not parse %s JSON to %s", method, kind, to)
+	}
+	head := json.offset
+	end, f := json.validNumberEndFlag()
+	if end == -1 {
+		return nil, 0, json.err
+	}
+	// only whitespaces are allowed after number
+	for i := end; i < json.tail; i++ {
+		switch json.data[i] {
+		case ' ', '\n', '\t', '\r':
+		default:
+			return nil, 0, SyntaxError{Number, i, json.data}
+		}
+	}
+	return json.data[head:end], f, nil
+}
+
+// ParseUint64 parses an unsigned integer Number json value to uint64,
+// number in float or scientific notation is accepted if it is an integer.
+// Negative number returns strconv.ErrRange.
+func (json *JSON) ParseUint64() (uint64, error) {
+	b, _, err := json.number("ParseUint64", "uint")
+	if err != nil {
+		return 0, err
+	}
+	u, neg, err := parseIntegral("ParseUint64", b)
+	if err != nil {
+		return 0, err
+	}
+	if neg && u != 0 {
+		return 0, numError("ParseUint64", b, strconv.ErrRange)
+	}
+	return u, nil
+}
+
+// ParseInt32 parses an int Number json value to int32,
+// it returns strconv.ErrRange if the value overflows int32
+func (json *JSON) ParseInt32() (int32, error) {
+	b, _, err := json.number("ParseInt32", "int")
+	if err != nil {
+		return 0, err
+	}
+	u, neg, err := parseIntegral("ParseInt32", b)
+	if err != nil {
+		return 0, err
+	}
+	i, err := toInt64("ParseInt32", b, u, neg)
+	if err != nil || i < math.MinInt32 || i > math.MaxInt32 {
+		return 0, numError("ParseInt32", b, strconv.ErrRange)
+	}
+	return int32(i), nil
+}
+
+// ParseNumber returns the Number json value as json.Number
+// of encoding/json without converting it
+func (json *JSON) ParseNumber() (stdjson.Number, error) {
+	b, _, err := json.number("ParseNumber", "number")
+	if err != nil {
+		return "", err
+	}
+	return stdjson.Number(b), nil
+}
+
+// ParseBigInt parses an integer Number json value of any size to big.Int,
+// number in float or scientific notation is accepted if it is an integer
+func (json *JSON) ParseBigInt() (*big.Int, error) {
+	b, _, err := json.number("ParseBigInt", "big.Int")
+	if err != nil {
+		return nil, err
+	}
+	p := splitNumber(b)
+	lo, hi, exp := p.trim()
+	if exp < 0 {
+		return nil, numError("ParseBigInt", b, strconv.ErrSyntax)
+	}
+	if exp > maxBigExponent {
+		return nil, numError("ParseBigInt", b, strconv.ErrRange)
+	}
+	n := new(big.Int)
+	if lo == hi {
+		return n, nil
+	}
+	n.SetString(p.digits()[lo:hi], 10)
+	if exp > 0 {
+		n.Mul(n, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(exp)), nil))
+	}
+	if p.neg {
+		n.Neg(n)
+	}
+	return n, nil
+}
+
+// IsInteger reports whether the Number json value is an integer, it is
+// decided by the digits, so 10.0, 1e3 and 1e400 are integers but 1e-400
+// is not. It returns false if json is not a valid Number.
+func (json *JSON) IsInteger() bool {
+	b, _, err := json.number("IsInteger", "integer")
+	if err != nil {
+		return false
+	}
+	p := splitNumber(b)
+	_, _, exp := p.trim()
+	return exp >= 0
+}
+
+// ParseBigFloat parses a Number json value to big.Float,
+// the precision is big enough to hold all the digits.
+// The exponent which overflows big.Float is rounded to zero or infinity.
+func (json *JSON) ParseBigFloat() (*big.Float, error) {
+	b, _, err := json.number("ParseBigFloat", "big.Float")
+	if err != nil {
+		return nil, err
+	}
+	p := splitNumber(b)
+	// log2(10) < 3.33
+	prec := uint(p.len())*333/100 + 1
+	if prec < 64 {
+		prec = 64
+	}
+	return parseBigFloat("ParseBigFloat", b, prec)
+}
+
+// parseBigFloat parses the valid JSON number b to big.Float with prec,
+// the exponent which overflows big.Float is rounded to zero or infinity
+func parseBigFloat(fn string, b []byte, prec uint) (*big.Float, error) {
+	f, _, err := big.ParseFloat(string(b), 10, prec, big.ToNearestEven)
+	if err == nil {
+		return f, nil
+	}
+	p := splitNumber(b)
+	lo, hi, exp := p.trim()
+	switch {
+	case lo == hi:
+		f = new(big.Float)
+	case exp > 0:
+		f = new(big.Float).SetInf(false)
+	case exp < 0:
+		f = new(big.Float)
+	default:
+		return nil, numError(fn, b, err)
+	}
+	f.SetPrec(prec)
+	if p.neg {
+		f.Neg(f)
+	}
+	return f, nil
+}
+
+// ParseDecimal parses a Number json value to exact Decimal without float
+// rounding. The scale is kept as it appears, so 10.50 has scale 2.
+func (json *JSON) ParseDecimal() (Decimal, error) {
+	b, _, err := json.number("ParseDecimal", "decimal")
+	if err != nil {
+		return Decimal{}, err
+	}
+	p := splitNumber(b)
+	scale := len(p.fracPart) - p.exp
+	if scale > maxBigExponent || scale < -maxBigExponent {
+		return Decimal{}, numError("ParseDecimal", b, strconv.ErrRange)
+	}
+	n, _ := new(big.Int).SetString(p.digits(), 10)
+	if scale < 0 {
+		n.Mul(n, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(-scale)), nil))
+		scale = 0
+	}
+	if p.neg {
+		n.Neg(n)
+	}
+	return Decimal{Unscaled: n, Scale: scale}, nil
+}
+
+// parseIntBytes parses the JSON integer b in plain notation to int64
+// without converting it to string
+func parseIntBytes(fn string, b []byte) (int64, error) {
+	neg := false
+	digits := b
+	if len(digits) > 0 && digits[0] == '-' {
+		neg = true
+		digits = digits[1:]
+	}
+	var u uint64
+	for _, c := range digits {
+		if !isDigit(c) {
+			return 0, numError(fn, b, strconv.ErrSyntax)
+		}
+		d := uint64(c - '0')
+		if u > (math.MaxUint64-d)/10 {
+			return 0, numError(fn, b, strconv.ErrRange)
+		}
+		u = u*10 + d
+	}
+	return toInt64(fn, b, u, neg)
+}
+
+// parseFloatBytes parses the valid JSON number b to float64 without
+// converting it to string, f is the flag returned by validNumberEndFlag.
+// It falls back to strconv in the rare cases which Eisel-Lemire can not
+// decide.
+func parseFloatBytes(b []byte, f flag) (float64, error) {
+	neg := contains(f, flagIsNegative)
+	i := 0
+	if neg {
+		i++
+	}
+
+	// man holds at most 19 significant digits, the others are truncated
+	var man uint64
+	exp10 := 0
+	nd := 0
+	trunc := false
+	dot := false
+	for ; i < len(b); i++ {
+		c := b[i]
+		if c == '.' {
+			dot = true
+			continue
+		}
+		if !isDigit(c) {
+			break
+		}
+		if nd == 0 && c == '0' {
+			// leading zeros are not significant
+			if dot {
+				exp10--
+			}
+			continue
+		}
+		if nd < 19 {
+			man = man*10 + uint64(c-'0')
+			nd++
+			if dot {
+				exp10--
+			}
+			continue
+		}
+		if c != '0' {
+			trunc = true
+		}
+		if !dot {
+			exp10++
+		}
+	}
+
+	if contains(f, flagIsScientific) && i < len(b) {
+		// skip e or E
+		i++
+		expNeg := false
+		if b[i] == '-' || b[i] == '+' {
+			expNeg = b[i] == '-'
+			i++
+		}
+		e := 0
+		for ; i < len(b); i++ {
+			if e < 10000 {
+				e = e*10 + int(b[i]-'0')
+			}
+		}
+		if expNeg {
+			e = -e
+		}
+		exp10 += e
+	}
+
+	if !trunc {
+		if v, ok := exactFloat64(man, exp10, neg); ok {
+			return v, nil
+		}
+		if v, ok := eiselLemire64(man, exp10, neg); ok {
+			return v, nil
+		}
+	} else {
+		// the truncated value is in [man, man+1) * 10^exp10
+		v, ok := eiselLemire64(man, exp10, neg)
+		if ok {
+			if v1, ok1 := eiselLemire64(man+1, exp10, neg); ok1 && v == v1 {
+				return v, nil
+			}
+		}
+	}
+	return strconv.ParseFloat(string(b), 64)
+}
@@ -0,0 +1,213 @@
+package jzon
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestJSON_ParseUint64(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    uint64
+		wantErr bool
+	}{
+		{"1", `123`, 123, false},
+		{"2", `18446744073709551615`, 18446744073709551615, false},
+		{"3", `18446744073709551616`, 0, true},
+		{"4", `-1`, 0, true},
+		{"5", `-0`, 0, false},
+		{"6", `1.5e1`, 15, false},
+		{"7", `1.5`, 0, true},
+		{"8", `1e20`, 0, true},
+		{"9", `0.0e10`, 0, false},
+		{"10", `true`, 0, true},
+		{"11", ` 42 `, 42, false},
+		{"12", `42 x`, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FromString(tt.data).ParseUint64()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("JSON.ParseUint64() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("JSON.ParseUint64() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSON_ParseInt32(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    int32
+		wantErr bool
+	}{
+		{"1", `123`, 123, false},
+		{"2", `2147483647`, 2147483647, false},
+		{"3", `2147483648`, 0, true},
+		{"4", `-2147483648`, -2147483648, false},
+		{"5", `-2147483649`, 0, true},
+		{"6", `1e3`, 1000, false},
+		{"7", `1e100`, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FromString(tt.data).ParseInt32()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("JSON.ParseInt32() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("JSON.ParseInt32() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSON_ParseNumber(t *testing.T) {
+	json := FromString(`{"n": -1.5e+10}`)
+	if err := json.Path("n"); err != nil {
+		t.Fatalf("JSON.Path() error = %v", err)
+	}
+	got, err := json.ParseNumber()
+	if err != nil || got.String() != "-1.5e+10" {
+		t.Errorf("JSON.ParseNumber() = %v, %v, want -1.5e+10, nil", got, err)
+	}
+	if f, err := got.Float64(); err != nil || f != -1.5e10 {
+		t.Errorf("Number.Float64() = %v, %v, want -1.5e10, nil", f, err)
+	}
+	if _, err := FromString(`"1"`).ParseNumber(); err == nil {
+		t.Errorf("JSON.ParseNumber() error = nil, want error")
+	}
+}
+
+func TestJSON_ParseBigInt(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    string
+		wantErr bool
+	}{
+		{"1", `123456789012345678901234567890`, "123456789012345678901234567890", false},
+		{"2", `-123456789012345678901234567890`, "-123456789012345678901234567890", false},
+		{"3", `1.5e30`, "1500000000000000000000000000000", false},
+		{"4", `0`, "0", false},
+		{"5", `1.5`, "", true},
+		{"6", `1e1000000`, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FromString(tt.data).ParseBigInt()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("JSON.ParseBigInt() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && got.String() != tt.want {
+				t.Errorf("JSON.ParseBigInt() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSON_ParseBigFloat(t *testing.T) {
+	got, err := FromString(`3.14159265358979323846264338327950288`).ParseBigFloat()
+	if err != nil {
+		t.Fatalf("JSON.ParseBigFloat() error = %v", err)
+	}
+	if s := got.Text('f', 35); s != "3.14159265358979323846264338327950288" {
+		t.Errorf("JSON.ParseBigFloat() = %v, want 3.14159265358979323846264338327950288", s)
+	}
+
+	// the exponent overflows big.Float
+	for data, want := range map[string]string{
+		`1e99999999999`:   "+Inf",
+		`-1e99999999999`:  "-Inf",
+		`1e-99999999999`:  "0",
+		`-1e-99999999999`: "-0",
+		`0e99999999999`:   "0",
+		`1e999999999`:     "+Inf",
+	} {
+		got, err := FromString(data).ParseBigFloat()
+		if err != nil {
+			t.Errorf("JSON.ParseBigFloat() of %s error = %v", data, err)
+			continue
+		}
+		if s := got.Text('g', 10); s != want {
+			t.Errorf("JSON.ParseBigFloat() of %s = %v, want %v", data, s, want)
+		}
+	}
+}
+
+func TestJSON_IsInteger(t *testing.T) {
+	tests := []struct {
+		data string
+		want bool
+	}{
+		{`10`, true},
+		{`-0`, true},
+		{`10.0`, true},
+		{`1.50e1`, true},
+		{`1e3`, true},
+		{`1e999999999`, true},
+		{`9223372036854775808`, true},
+		{`0e-99999999999`, true},
+		{`1.5`, false},
+		{`9007199254740993.5`, false},
+		{`1e-1`, false},
+		{`1e-99999999999`, false},
+		{`"1"`, false},
+		{`1x`, false},
+	}
+	for _, tt := range tests {
+		if got := FromString(tt.data).IsInteger(); got != tt.want {
+			t.Errorf("JSON.IsInteger() of %s = %v, want %v", tt.data, got, tt.want)
+		}
+	}
+}
+
+func TestJSON_ParseDecimal(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      string
+		want      string
+		wantScale int
+		wantErr   bool
+	}{
+		{"1", `10.50`, "10.50", 2, false},
+		{"2", `-0.01`, "-0.01", 2, false},
+		{"3", `0.1`, "0.1", 1, false},
+		{"4", `1.5e3`, "1500", 0, false},
+		{"5", `15e-4`, "0.0015", 4, false},
+		{"6", `123`, "123", 0, false},
+		{"7", `null`, "", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FromString(tt.data).ParseDecimal()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("JSON.ParseDecimal() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			if got.String() != tt.want || got.Scale != tt.wantScale {
+				t.Errorf("JSON.ParseDecimal() = %v scale %v, want %v scale %v", got, got.Scale, tt.want, tt.wantScale)
+			}
+		})
+	}
+}
+
+func TestDecimal_Rat(t *testing.T) {
+	// 0.1 + 0.2 is exactly 0.3 in decimal
+	a, _ := FromString(`0.1`).ParseDecimal()
+	b, _ := FromString(`0.2`).ParseDecimal()
+	sum := new(big.Rat).Add(a.Rat(), b.Rat())
+	if sum.Cmp(big.NewRat(3, 10)) != 0 {
+		t.Errorf("Decimal.Rat() sum = %v, want 3/10", sum)
+	}
+}
@@ -0,0 +1,158 @@
+package jzon
+
+import "fmt"
+
+// Member is a key and value pair of OrderedObject
+type Member struct {
+	Key   string
+	Value *JSON
+}
+
+// OrderedObject is an object which keeps the order of members as they
+// appear in document. The values are *JSON views, so the unchanged
+// values are written back by Marshal exactly as they were read.
+// It is suitable for editing config files with minimal diffs.
+type OrderedObject struct {
+	members []Member
+	// index maps key to the position of its first member
+	index map[string]int
+}
+
+// NewOrderedObject returns an empty OrderedObject,
+// the zero value is ready to use too
+func NewOrderedObject() *OrderedObject {
+	return &OrderedObject{
+		index: make(map[string]int),
+	}
+}
+
+// OrderedObject collects the remaining members of iter into OrderedObject,
+// the iterator is reset after that
+func (iter *ObjectIter) OrderedObject() *OrderedObject {
+	o := NewOrderedObject()
+	for iter.Next() {
+		o.append(iter.Key(), iter.Value())
+	}
+	iter.Reset()
+	return o
+}
+
+// OrderedObject returns the object json value as OrderedObject
+func (json *JSON) OrderedObject() (*OrderedObject, error) {
+	if kind := json.Kind(); kind != Object {
+		return nil, fmt.Errorf("OrderedObject: Can not parse %s JSON to object", kind)
+	}
+	v, err := json.validView()
+	if err != nil {
+		return nil, err
+	}
+	iter, err := v.UnsafeObject()
+	if err != nil {
+		return nil, err
+	}
+	return iter.OrderedObject(), nil
+}
+
+func (o *OrderedObject) append(key string, value *JSON) {
+	if o.index == nil {
+		o.index = make(map[string]int)
+	}
+	if _, ok := o.index[key]; !ok {
+		o.index[key] = len(o.members)
+	}
+	o.members = append(o.members, Member{key, value})
+}
+
+// Len returns the number of members
+func (o *OrderedObject) Len() int {
+	return len(o.members)
+}
+
+// Members returns the members in order, it must not be modified
+func (o *OrderedObject) Members() []Member {
+	return o.members
+}
+
+// Keys returns the keys in order
+func (o *OrderedObject) Keys() []string {
+	keys := make([]string, 0, len(o.members))
+	for _, m := range o.members {
+		keys = append(keys, m.Key)
+	}
+	return keys
+}
+
+// Get returns the value of the first member with key
+func (o *OrderedObject) Get(key string) (*JSON, bool) {
+	i, ok := o.index[key]
+	if !ok {
+		return nil, false
+	}
+	return o.members[i].Value, true
+}
+
+// Set replaces the value of the first member with key in place,
+// or appends a new member if key does not exist
+func (o *OrderedObject) Set(key string, value *JSON) {
+	if i, ok := o.index[key]; ok {
+		o.members[i].Value = value
+		return
+	}
+	o.append(key, value)
+}
+
+// SetValue is like Set, but v is encoded by Marshal first
+func (o *OrderedObject) SetValue(key string, v interface{}) error {
+	b, err := Marshal(v)
+	if err != nil {
+		return err
+	}
+	o.Set(key, FromBytes(b))
+	return nil
+}
+
+// Delete removes all members with key, it returns false if key does not exist
+func (o *OrderedObject) Delete(key string) bool {
+	if _, ok := o.index[key]; !ok {
+		return false
+	}
+	members := o.members[:0]
+	for _, m := range o.members {
+		if m.Key != key {
+			members = append(members, m)
+		}
+	}
+	for i := len(members); i < len(o.members); i++ {
+		o.members[i] = Member{}
+	}
+	o.members = members
+
+	o.index = make(map[string]int, len(o.members))
+	for i, m := range o.members {
+		if _, ok := o.index[m.Key]; !ok {
+			o.index[m.Key] = i
+		}
+	}
+	return true
+}
+
+// MarshalJSON implements json.Marshaler of encoding/json
+func (o *OrderedObject) MarshalJSON() ([]byte, error) {
+	return Marshal(o)
+}
+
+func (o *OrderedObject) appendJSON(dst []byte) ([]byte, error) {
+	dst = append(dst, '{')
+	for i, m := range o.members {
+		if i > 0 {
+			dst = append(dst, ',')
+		}
+		dst = appendQuote(dst, m.Key)
+		dst = append(dst, ':')
+		var err error
+		if dst, err = AppendMarshal(dst, m.Value); err != nil {
+			return dst, err
+		}
+	}
+	return append(dst, '}'), nil
+}
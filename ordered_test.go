@@ -0,0 +1,133 @@
+package jzon
+
+import (
+	stdjson "encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestJSON_OrderedObject(t *testing.T) {
+	data := `{"name": "app", "port": 8080, "tags": ["a", "b"], "debug": false}`
+
+	o, err := FromString(data).OrderedObject()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := o.Keys(), []string{"name", "port", "tags", "debug"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("OrderedObject.Keys() = %v, want %v", got, want)
+	}
+	if o.Len() != 4 {
+		t.Errorf("OrderedObject.Len() = %v, want 4", o.Len())
+	}
+
+	// round trip
+	got, err := Marshal(o)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"name":"app","port":8080,"tags":["a", "b"],"debug":false}`
+	if string(got) != want {
+		t.Errorf("Marshal(OrderedObject) = %s, want %s", got, want)
+	}
+
+	// edit
+	if v, ok := o.Get("port"); !ok || v.String() != "8080" {
+		t.Errorf("OrderedObject.Get(port) = %v %v, want 8080 true", v, ok)
+	}
+	if err := o.SetValue("port", 9090); err != nil {
+		t.Fatal(err)
+	}
+	o.Set("extra", FromString(`{"k": null}`))
+	if !o.Delete("debug") {
+		t.Errorf("OrderedObject.Delete(debug) = false, want true")
+	}
+	if o.Delete("debug") {
+		t.Errorf("OrderedObject.Delete(debug) again = true, want false")
+	}
+	if _, ok := o.Get("debug"); ok {
+		t.Errorf("OrderedObject.Get(debug) after Delete = true, want false")
+	}
+	if v, ok := o.Get("extra"); !ok || v.String() != `{"k": null}` {
+		t.Errorf("OrderedObject.Get(extra) = %v %v", v, ok)
+	}
+
+	got, err = stdjson.Marshal(o)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = `{"name":"app","port":9090,"tags":["a","b"],"extra":{"k":null}}`
+	if string(got) != want {
+		t.Errorf("json.Marshal(OrderedObject) = %s, want %s", got, want)
+	}
+
+	if _, err := FromString(`[1]`).OrderedObject(); err == nil {
+		t.Errorf("JSON.OrderedObject() of array error = nil, want error")
+	}
+	if _, err := FromString(`{"a": }`).OrderedObject(); err == nil {
+		t.Errorf("JSON.OrderedObject() of invalid object error = nil, want error")
+	}
+}
+
+func TestOrderedObject_Set_Invalid(t *testing.T) {
+	o := NewOrderedObject()
+	o.Set("a", FromString(`1`))
+	o.Set("b", FromString(`{bad`))
+	if got, err := Marshal(o); err == nil {
+		t.Errorf("Marshal(OrderedObject) = %s, want error", got)
+	}
+	o.Set("b", FromString(` [2] `))
+	if got, err := Marshal(o); err != nil || string(got) != `{"a":1,"b":[2]}` {
+		t.Errorf("Marshal(OrderedObject) = %s %v", got, err)
+	}
+}
+
+func TestOrderedObject_DuplicateKey(t *testing.T) {
+	data := `{"a": 1, "b": 2, "a": 3}`
+
+	o, err := FromString(data).OrderedObject()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := o.Get("a"); v.String() != "1" {
+		t.Errorf("OrderedObject.Get(a) = %v, want 1", v)
+	}
+	if got, _ := Marshal(o); string(got) != `{"a":1,"b":2,"a":3}` {
+		t.Errorf("Marshal(OrderedObject) = %s", got)
+	}
+	o.Delete("a")
+	if got, _ := Marshal(o); string(got) != `{"b":2}` {
+		t.Errorf("Marshal(OrderedObject) after Delete = %s", got)
+	}
+
+	o, err = FromString(data).SetDuplicateKeyPolicy(DuplicateKeyLastWins).OrderedObject()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := Marshal(o); string(got) != `{"b":2,"a":3}` {
+		t.Errorf("Marshal(OrderedObject) with LastWins = %s", got)
+	}
+
+	var zero OrderedObject
+	zero.Set("x", FromString(`1`))
+	if got, _ := Marshal(&zero); string(got) != `{"x":1}` {
+		t.Errorf("Marshal(zero OrderedObject) = %s", got)
+	}
+}
+
+func TestObjectIter_OrderedObject(t *testing.T) {
+	json := FromString(jsonStr)
+	if err := json.Path("object"); err != nil {
+		t.Fatal(err)
+	}
+	iter, err := json.Object()
+	if err != nil {
+		t.Fatal(err)
+	}
+	o := iter.OrderedObject()
+	if got, want := o.Keys(), []string{"list", "k2", "o", "o2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ObjectIter.OrderedObject().Keys() = %v, want %v", got, want)
+	}
+	if v, _ := o.Get("o2"); v.GetString("", "k1") != "string" {
+		t.Errorf("OrderedObject.Get(o2).k1 = %v, want string", v)
+	}
+}
@@ -0,0 +1,137 @@
+package jzon
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+)
+
+// ParallelOptions controls ParallelEach and ParallelMap
+type ParallelOptions struct {
+	// Workers is the number of goroutines which call fn,
+	// runtime.GOMAXPROCS(0) is used if it is not positive
+	Workers int
+	// Buffer is the number of elements waiting for workers,
+	// 2*Workers is used if it is not positive
+	Buffer int
+}
+
+// ParallelEach calls fn for every element of array arr concurrently. The
+// elements are split by the unsafe block scanner and dispatched to a pool
+// of workers, so the order of calls is not defined and can change from
+// run to run. There is no option to call fn in order, that would run fn
+// one by one; use ParallelMap which keeps the results in the order of
+// elements, or handle the index i in fn.
+//
+// The first error returned by fn stops dispatching and is returned, the
+// running calls are not interrupted. It also stops if ctx is done and
+// returns ctx.Err(). A syntax error found while splitting is returned
+// after the elements before it are processed.
+func ParallelEach(ctx context.Context, arr *JSON, options ParallelOptions, fn func(i int, v *JSON) error) error {
+	return parallel(ctx, "ParallelEach", arr, options, func(i int, v *JSON, _ *interface{}) error {
+		return fn(i, v)
+	}, nil)
+}
+
+// ParallelMap is like ParallelEach, but the results of fn are
+// returned in the order of elements
+func ParallelMap(ctx context.Context, arr *JSON, options ParallelOptions, fn func(i int, v *JSON) (interface{}, error)) ([]interface{}, error) {
+	var slots []*interface{}
+	err := parallel(ctx, "ParallelMap", arr, options, func(i int, v *JSON, slot *interface{}) error {
+		r, err := fn(i, v)
+		*slot = r
+		return err
+	}, &slots)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]interface{}, len(slots))
+	for i, slot := range slots {
+		results[i] = *slot
+	}
+	return results, nil
+}
+
+type parallelJob struct {
+	index int
+	value *JSON
+	slot  *interface{}
+}
+
+// parallel runs fn in workers, the slot of every element is appended to
+// slots if it is not nil
+func parallel(ctx context.Context, method string, arr *JSON, options ParallelOptions, fn func(int, *JSON, *interface{}) error, slots *[]*interface{}) error {
+	if kind := arr.Kind(); kind != Array {
+		return fmt.Errorf("%s: Can not iterate %s JSON", method, kind)
+	}
+	iter, err := arr.UnsafeArray()
+	if err != nil {
+		return err
+	}
+
+	workers := options.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	buffer := options.Buffer
+	if buffer <= 0 {
+		buffer = 2 * workers
+	}
+
+	cctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		once     sync.Once
+		firstErr error
+		wg       sync.WaitGroup
+	)
+	fail := func(err error) {
+		once.Do(func() {
+			firstErr = err
+			cancel()
+		})
+	}
+
+	jobs := make(chan parallelJob, buffer)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				if cctx.Err() != nil {
+					// drain the jobs after stopped
+					continue
+				}
+				if err := fn(job.index, job.value, job.slot); err != nil {
+					fail(err)
+				}
+			}
+		}()
+	}
+
+Loop:
+	for iter.Next() {
+		job := parallelJob{index: iter.Index(), value: iter.Value()}
+		if slots != nil {
+			job.slot = new(interface{})
+			*slots = append(*slots, job.slot)
+		}
+		select {
+		case jobs <- job:
+		case <-cctx.Done():
+			break Loop
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return iter.Err()
+}
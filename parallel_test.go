@@ -0,0 +1,153 @@
+package jzon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func numbers(n int) string {
+	var b strings.Builder
+	b.WriteString("[")
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		fmt.Fprintf(&b, `{"n": %d}`, i)
+	}
+	b.WriteString("]")
+	return b.String()
+}
+
+func TestParallelEach(t *testing.T) {
+	arr := FromString(numbers(1000))
+	var sum, calls int64
+	err := ParallelEach(context.Background(), arr, ParallelOptions{Workers: 4}, func(i int, v *JSON) error {
+		if got := v.GetInt(-1, "n"); got != int64(i) {
+			return fmt.Errorf("element %d = %d", i, got)
+		}
+		atomic.AddInt64(&sum, int64(i))
+		atomic.AddInt64(&calls, 1)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls != 1000 || sum != 999*1000/2 {
+		t.Errorf("ParallelEach() calls = %d, sum = %d", calls, sum)
+	}
+
+	// empty array
+	err = ParallelEach(context.Background(), FromString(`[]`), ParallelOptions{}, func(i int, v *JSON) error {
+		t.Errorf("ParallelEach() of [] calls fn")
+		return nil
+	})
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestParallelEach_Error(t *testing.T) {
+	errStop := errors.New("stop")
+	var calls int64
+	err := ParallelEach(context.Background(), FromString(numbers(10000)), ParallelOptions{Workers: 2, Buffer: 1}, func(i int, v *JSON) error {
+		atomic.AddInt64(&calls, 1)
+		if i == 10 {
+			return errStop
+		}
+		return nil
+	})
+	if err != errStop {
+		t.Errorf("ParallelEach() error = %v, want %v", err, errStop)
+	}
+	if calls == 10000 {
+		t.Errorf("ParallelEach() does not stop after error")
+	}
+
+	// syntax error after some elements
+	var processed int64
+	err = ParallelEach(context.Background(), FromString(`[1, 2, 3 4]`), ParallelOptions{}, func(i int, v *JSON) error {
+		atomic.AddInt64(&processed, 1)
+		return nil
+	})
+	if _, ok := err.(SyntaxError); !ok {
+		t.Errorf("ParallelEach() of malformed array error = %v, want SyntaxError", err)
+	}
+	if processed != 3 {
+		t.Errorf("ParallelEach() of malformed array processed %d elements, want 3", processed)
+	}
+
+	err = ParallelEach(context.Background(), FromString(`{}`), ParallelOptions{}, func(i int, v *JSON) error {
+		return nil
+	})
+	if err == nil {
+		t.Errorf("ParallelEach() of object error = nil, want error")
+	}
+}
+
+func TestParallelEach_Context(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := ParallelEach(ctx, FromString(numbers(100)), ParallelOptions{}, func(i int, v *JSON) error {
+		return nil
+	})
+	if err != context.Canceled {
+		t.Errorf("ParallelEach() with canceled context error = %v, want %v", err, context.Canceled)
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	var calls int64
+	err = ParallelEach(ctx, FromString(numbers(10000)), ParallelOptions{Workers: 2}, func(i int, v *JSON) error {
+		atomic.AddInt64(&calls, 1)
+		time.Sleep(time.Millisecond)
+		return nil
+	})
+	if err != context.DeadlineExceeded {
+		t.Errorf("ParallelEach() with timeout error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if calls == 10000 {
+		t.Errorf("ParallelEach() does not stop after timeout")
+	}
+}
+
+func TestParallelMap(t *testing.T) {
+	results, err := ParallelMap(context.Background(), FromString(numbers(200)), ParallelOptions{Workers: 8}, func(i int, v *JSON) (interface{}, error) {
+		// finish in random order
+		time.Sleep(time.Duration(i%7) * 100 * time.Microsecond)
+		return v.GetInt(-1, "n") * 2, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 200 {
+		t.Fatalf("ParallelMap() returns %d results, want 200", len(results))
+	}
+	for i, r := range results {
+		if r != int64(i*2) {
+			t.Errorf("ParallelMap()[%d] = %v, want %d", i, r, i*2)
+		}
+	}
+
+	_, err = ParallelMap(context.Background(), FromString(`[1, 2, "x"]`), ParallelOptions{}, func(i int, v *JSON) (interface{}, error) {
+		return v.ParseInt64()
+	})
+	if err == nil {
+		t.Errorf("ParallelMap() error = nil, want error")
+	}
+}
+
+func BenchmarkParallelEach(b *testing.B) {
+	arr := FromString(numbers(10000))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		ParallelEach(context.Background(), arr, ParallelOptions{}, func(i int, v *JSON) error {
+			_, err := v.Interface()
+			return err
+		})
+	}
+}
@@ -0,0 +1,265 @@
+package jzon
+
+import (
+	"strings"
+	"testing"
+)
+
+// The recursive version of validator which is replaced by validBlockEnd,
+// it is kept as reference of error semantics and for benchmarks.
+
+func (json *JSON) recursiveValueEnd() (int, Kind) {
+	switch kind := json.Predict(); kind {
+	case Object:
+		return json.recursiveObjectEnd(), kind
+	case Array:
+		return json.recursiveArrayEnd(), kind
+	default:
+		return json.validValueEnd()
+	}
+}
+
+func (json *JSON) recursiveArrayEnd() int {
+	validEnd := func() int {
+		flag := flagNeedStart
+		elements := 0
+		for {
+			c, ok := json.nextToken()
+			if !ok {
+				return -json.offset - 1
+			}
+			switch c {
+
+			case ',':
+				if !contains(flag, flagNeedComma) {
+					return -json.offset - 1
+				}
+				flag = remove(flag, flagNeedComma, flagNeedEnd)
+				flag = add(flag, flagNeedValue)
+				json.offset++
+			case ']':
+				if !contains(flag, flagNeedEnd) {
+					return -json.offset - 1
+				}
+				json.offset++
+				return json.offset
+			case '[':
+				if contains(flag, flagNeedValue) {
+					// [[1,2], [3,4]]
+					goto DefaultCase
+				}
+
+				if !contains(flag, flagNeedStart) {
+					return -json.offset - 1
+				}
+				flag = remove(flag, flagNeedStart)
+				flag = add(flag, flagNeedValue, flagNeedEnd)
+				if !json.checkDepth(json.depth, json.offset) {
+					return -1
+				}
+				json.offset++
+				break
+			DefaultCase: // label
+				fallthrough
+			default:
+				if !contains(flag, flagNeedValue) {
+					return -json.offset - 1
+				}
+				elements++
+				if !json.checkElements(elements, json.offset) {
+					return -1
+				}
+				end, _ := json.recursiveValueEnd()
+				if end == -1 {
+					return -1
+				}
+				flag = remove(flag, flagNeedValue)
+				flag = add(flag, flagNeedComma, flagNeedEnd)
+				json.offset = end
+			}
+		}
+	}
+
+	now := json.offset
+	json.depth++
+	end := validEnd()
+	json.depth--
+	if end < 0 {
+		if json.err == nil {
+			json.err = SyntaxError{Array, -(end + 1), json.data}
+		}
+		end = -1
+	}
+
+	json.offset = now
+	return end
+}
+
+func (json *JSON) recursiveObjectEnd() int {
+	validEnd := func() int {
+		flag := flagNeedStart
+		members := 0
+		var seen map[string]int
+		if json.dupPolicy == DuplicateKeyReject {
+			seen = make(map[string]int)
+		}
+		for {
+			c, ok := json.nextToken()
+			if !ok {
+				return -json.offset - 1
+			}
+			switch c {
+			case '{':
+				if !contains(flag, flagNeedStart) {
+					return -json.offset - 1
+				}
+				flag = remove(flag, flagNeedStart)
+				flag = add(flag, flagNeedKey, flagNeedEnd)
+				if !json.checkDepth(json.depth, json.offset) {
+					return -1
+				}
+				json.offset++
+			case '"':
+				if !contains(flag, flagNeedKey) {
+					return -json.offset - 1
+				}
+				members++
+				if !json.checkMembers(members, json.offset) {
+					return -1
+				}
+				end := json.validStringEnd()
+				if end == -1 {
+					return -1
+				}
+				if seen != nil {
+					key, _ := unquote(json.data[json.offset:end])
+					if first, ok := seen[key]; ok {
+						json.err = DuplicateKeyError{key, first, json.offset}
+						return -1
+					}
+					seen[key] = json.offset
+				}
+				flag = remove(flag, flagNeedKey, flagNeedEnd)
+				flag = add(flag, flagNeedColon)
+				json.offset = end // move to end
+			case ':':
+				if !contains(flag, flagNeedColon) {
+					return -json.offset - 1
+				}
+				json.offset++
+				end, _ := json.recursiveValueEnd()
+				if end == -1 {
+					return -1
+				}
+				flag = remove(flag, flagNeedColon)
+				flag = add(flag, flagNeedComma, flagNeedEnd) // clean flagColon
+				json.offset = end
+			case ',':
+				if !contains(flag, flagNeedComma) {
+					return -json.offset - 1
+				}
+				flag = remove(flag, flagNeedComma, flagNeedEnd)
+				flag = add(flag, flagNeedKey)
+				json.offset++
+			case '}':
+				if !contains(flag, flagNeedEnd) {
+					return -json.offset - 1
+				}
+				json.offset++
+				return json.offset
+			default:
+				// the original version loops forever here
+				return -json.offset - 1
+			}
+		}
+	}
+	now := json.offset
+	json.depth++
+	end := validEnd()
+	json.depth--
+	if end < 0 {
+		if json.err == nil {
+			json.err = SyntaxError{Object, -(end + 1), json.data}
+		}
+		end = -1
+	}
+	json.offset = now
+	return end
+}
+
+func TestJSON_validBlockEnd(t *testing.T) {
+	tests := []string{
+		`[]`, `{}`, `[1,2,3]`, `{"a":1}`, `[[1,2], [3,4]]`, `{"a":{"b":[{}, []]}}`,
+		jsonStr, `[`, `{`, `[1,]`, `[,1]`, `[1 2]`, `{"a"}`, `{"a":}`, `{"a":1,}`,
+		`{,}`, `{"a" 1}`, `{"a":1 "b":2}`, `[1]]`, `{"a":[1,2}`, `[{"a":1]`,
+		`{1:2}`, `{"a":1 x}`, `[tru]`, `[01]`, `["\x"]`, `[1, "a", true, null, {"b": -0.1e+2}]`,
+		`  [ 1 , { "a" : [ ] } ]  `, `[[[[[[[[[[]]]]]]]]]]`, `[[[[[[[[[[]]]]]]]]]`,
+		`{"a":1, "a":2}`, `[{"a":1, "a":2}]`,
+	}
+	policies := []DuplicateKeyPolicy{DuplicateKeyAllow, DuplicateKeyReject}
+	limits := []Limits{{}, {MaxDepth: 3, MaxObjectMembers: 1, MaxArrayElements: 2}}
+	for i, data := range tests {
+		for _, policy := range policies {
+			for _, limit := range limits {
+				want := FromString(data).SetDuplicateKeyPolicy(policy).SetLimits(limit)
+				wantEnd, _ := want.recursiveValueEnd()
+				got := FromString(data).SetDuplicateKeyPolicy(policy).SetLimits(limit)
+				gotEnd, _ := got.validValueEnd()
+				if gotEnd != wantEnd || got.offset != want.offset || errString(got.err) != errString(want.err) {
+					t.Errorf("%d: JSON.validValueEnd(%q) = %v, %v, %v, want %v, %v, %v",
+						i, data, gotEnd, got.offset, got.err, wantEnd, want.offset, want.err)
+				}
+			}
+		}
+	}
+}
+
+func TestJSON_validBlockEnd_DeepNesting(t *testing.T) {
+	n := 1000000
+	data := strings.Repeat("[", n) + strings.Repeat("]", n)
+	if err := FromString(data).CheckValid(); err != nil {
+		t.Errorf("JSON.CheckValid() error = %v, want nil", err)
+	}
+	if err := FromString(data[:len(data)-1]).CheckValid(); err == nil {
+		t.Errorf("JSON.CheckValid() error = nil, want error")
+	}
+}
+
+func BenchmarkJSON_validValueEnd(b *testing.B) {
+	data := []byte(jsonStr)
+	b.SetBytes(int64(len(data)))
+	for i := 0; i < b.N; i++ {
+		FromBytes(data).validValueEnd()
+	}
+}
+
+func BenchmarkJSON_recursiveValueEnd(b *testing.B) {
+	data := []byte(jsonStr)
+	b.SetBytes(int64(len(data)))
+	for i := 0; i < b.N; i++ {
+		FromBytes(data).recursiveValueEnd()
+	}
+}
+
+func BenchmarkJSON_validValueEnd_Nested(b *testing.B) {
+	data := []byte(strings.Repeat(`{"a":[`, 100) + strings.Repeat(`]}`, 100))
+	b.SetBytes(int64(len(data)))
+	for i := 0; i < b.N; i++ {
+		FromBytes(data).validValueEnd()
+	}
+}
+
+func BenchmarkJSON_recursiveValueEnd_Nested(b *testing.B) {
+	data := []byte(strings.Repeat(`{"a":[`, 100) + strings.Repeat(`]}`, 100))
+	b.SetBytes(int64(len(data)))
+	for i := 0; i < b.N; i++ {
+		FromBytes(data).recursiveValueEnd()
+	}
+}
+
+func errString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
@@ -0,0 +1,59 @@
+package schema
+
+import (
+	"fmt"
+	"strings"
+)
+
+// A CompileError occurs when the schema document is invalid,
+// Location is the absolute location of the invalid keyword
+type CompileError struct {
+	Location string
+	Err      error
+}
+
+func (e *CompileError) Error() string {
+	return fmt.Sprintf("schema: %s: %v", e.Location, e.Err)
+}
+
+// wrapError returns err as CompileError at location,
+// CompileError of subschema is returned as it is
+func wrapError(location string, err error) error {
+	if _, ok := err.(*CompileError); ok {
+		return err
+	}
+	return &CompileError{Location: location, Err: err}
+}
+
+// A Violation is a failed assertion of schema
+type Violation struct {
+	// InstanceLocation is the JSON Pointer of the value in instance,
+	// it is "" for the root value
+	InstanceLocation string
+	// SchemaLocation is the absolute location of the keyword,
+	// like "#/properties/id/type"
+	SchemaLocation string
+	Message        string
+}
+
+func (v Violation) String() string {
+	return fmt.Sprintf("%q: %s (%s)", v.InstanceLocation, v.Message, v.SchemaLocation)
+}
+
+// A ValidationError occurs when the instance does not match the schema,
+// it contains all violations in the order as they are found
+type ValidationError struct {
+	Violations []Violation
+}
+
+func (e *ValidationError) Error() string {
+	if len(e.Violations) == 1 {
+		return "schema: " + e.Violations[0].String()
+	}
+	lines := make([]string, 0, len(e.Violations)+1)
+	lines = append(lines, fmt.Sprintf("schema: %d violations", len(e.Violations)))
+	for _, v := range e.Violations {
+		lines = append(lines, "\t"+v.String())
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,45 @@
+package schema
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/zoumo/jzon"
+)
+
+var (
+	escaper   = strings.NewReplacer("~", "~0", "/", "~1")
+	unescaper = strings.NewReplacer("~1", "/", "~0", "~")
+)
+
+// escape escapes the reference token of JSON Pointer
+func escape(token string) string {
+	return escaper.Replace(token)
+}
+
+// lookup returns the value at JSON Pointer ptr of doc
+func lookup(doc *jzon.JSON, ptr string) (*jzon.JSON, error) {
+	v := doc.Clone()
+	if ptr == "" {
+		return v, nil
+	}
+	if !strings.HasPrefix(ptr, "/") {
+		return nil, fmt.Errorf("invalid JSON Pointer %q", ptr)
+	}
+	for _, token := range strings.Split(ptr[1:], "/") {
+		token = unescaper.Replace(token)
+		var key interface{} = token
+		if v.Kind() == jzon.Array {
+			i, err := strconv.Atoi(token)
+			if err != nil {
+				return nil, fmt.Errorf("invalid array index %q", token)
+			}
+			key = i
+		}
+		if err := v.Path(key); err != nil {
+			return nil, err
+		}
+	}
+	return v, nil
+}
@@ -0,0 +1,484 @@
+// Package schema validates jzon values against JSON Schema (draft 2020-12).
+//
+// A subset of the draft is supported, which covers most schemas used to
+// validate API payloads:
+//
+//	type, enum, const
+//	properties, required, additionalProperties, minProperties, maxProperties
+//	items, prefixItems, minItems, maxItems
+//	pattern, minLength, maxLength
+//	minimum, maximum, exclusiveMinimum, exclusiveMaximum
+//	allOf, anyOf, oneOf
+//	$ref, $defs, $id
+//
+// Other keywords are ignored. $ref is resolved against the $id of the root
+// schema, references within the document use JSON Pointer fragments like
+// "#/$defs/user", other documents are loaded by Compiler.Loader.
+// Patterns are compiled by regexp, so the RE2 syntax is used instead of
+// ECMA 262.
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/zoumo/jzon"
+)
+
+// A Loader loads the schema document of url for remote $ref,
+// the url has no fragment
+type Loader interface {
+	Load(url string) (*jzon.JSON, error)
+}
+
+// LoaderFunc is an adapter to use ordinary function as Loader
+type LoaderFunc func(url string) (*jzon.JSON, error)
+
+// Load calls f(url)
+func (f LoaderFunc) Load(url string) (*jzon.JSON, error) {
+	return f(url)
+}
+
+// Schema is a compiled JSON Schema, it is safe for concurrent use
+type Schema struct {
+	// location is the absolute location of schema, like "url#/$defs/a"
+	location string
+
+	// always is set for boolean schema
+	always *bool
+
+	ref   *Schema
+	types []string
+
+	enum     []interface{}
+	constant interface{}
+	hasConst bool
+
+	properties           map[string]*Schema
+	propertyNames        []string
+	required             []string
+	additionalProperties *Schema
+	minProperties        int
+	maxProperties        int
+
+	prefixItems []*Schema
+	items       *Schema
+	minItems    int
+	maxItems    int
+
+	pattern   *regexp.Regexp
+	minLength int
+	maxLength int
+
+	minimum          *big.Float
+	maximum          *big.Float
+	exclusiveMinimum *big.Float
+	exclusiveMaximum *big.Float
+
+	allOf []*Schema
+	anyOf []*Schema
+	oneOf []*Schema
+}
+
+// Location returns the absolute location of schema, it is the $id of
+// root schema followed by a JSON Pointer fragment, like "#/$defs/user"
+func (s *Schema) Location() string {
+	return s.location
+}
+
+// Compile compiles the schema document, remote $ref is not supported
+func Compile(doc *jzon.JSON) (*Schema, error) {
+	return new(Compiler).Compile(doc)
+}
+
+// Compiler compiles schema documents, the documents loaded by Loader
+// are cached and shared by the schemas it compiles
+type Compiler struct {
+	// Loader loads the documents of remote $ref, it is not
+	// supported if Loader is nil
+	Loader Loader
+
+	docs    map[string]*jzon.JSON
+	schemas map[string]*Schema
+	// refs are resolved after all schemas are compiled
+	refs []pendingRef
+}
+
+type pendingRef struct {
+	schema *Schema
+	base   string
+	ref    string
+}
+
+// Compile compiles the schema document, the $id of root schema is used
+// as the base url of $ref
+func (c *Compiler) Compile(doc *jzon.JSON) (*Schema, error) {
+	if err := doc.CheckValid(); err != nil {
+		return nil, err
+	}
+	base, err := rootID(doc)
+	if err != nil {
+		return nil, err
+	}
+	return c.compileDocument(base, doc)
+}
+
+func (c *Compiler) compileDocument(base string, doc *jzon.JSON) (*Schema, error) {
+	if c.docs == nil {
+		c.docs = make(map[string]*jzon.JSON)
+		c.schemas = make(map[string]*Schema)
+	}
+	// a new document replaces the one with same $id
+	c.docs[base] = doc
+	for location := range c.schemas {
+		if strings.HasPrefix(location, base+"#") {
+			delete(c.schemas, location)
+		}
+	}
+
+	s, err := c.compile(base, "", doc.Clone())
+	for err == nil && len(c.refs) > 0 {
+		r := c.refs[0]
+		c.refs = c.refs[1:]
+		r.schema.ref, err = c.resolve(r.base, r.ref)
+		if err != nil {
+			err = wrapError(r.schema.location+"/$ref", err)
+		}
+	}
+	if err != nil {
+		// the cached schemas may refer to unresolved ones
+		c.refs = nil
+		c.schemas = make(map[string]*Schema)
+		return nil, err
+	}
+	return s, nil
+}
+
+// rootID returns the $id of root schema without fragment
+func rootID(doc *jzon.JSON) (string, error) {
+	if doc.Kind() != jzon.Object {
+		return "", nil
+	}
+	v := doc.Clone()
+	if v.Path("$id") != nil {
+		return "", nil
+	}
+	id, err := v.ParseString()
+	if err != nil {
+		return "", &CompileError{Location: "#/$id", Err: errors.New("must be a string")}
+	}
+	u, err := url.Parse(id)
+	if err != nil {
+		return "", &CompileError{Location: "#/$id", Err: err}
+	}
+	u.Fragment = ""
+	return u.String(), nil
+}
+
+// resolve returns the schema referenced by ref which is relative to base
+func (c *Compiler) resolve(base, ref string) (*Schema, error) {
+	u, err := url.Parse(ref)
+	if err != nil {
+		return nil, fmt.Errorf("invalid $ref %q: %v", ref, err)
+	}
+	if base != "" {
+		b, err := url.Parse(base)
+		if err != nil {
+			return nil, err
+		}
+		u = b.ResolveReference(u)
+	}
+	ptr := u.Fragment
+	u.Fragment = ""
+	docURL := u.String()
+
+	if s, ok := c.schemas[docURL+"#"+ptr]; ok {
+		return s, nil
+	}
+
+	doc, ok := c.docs[docURL]
+	if !ok {
+		if c.Loader == nil {
+			return nil, fmt.Errorf("can not load %q, no Loader", docURL)
+		}
+		doc, err = c.Loader.Load(docURL)
+		if err != nil {
+			return nil, fmt.Errorf("can not load %q: %v", docURL, err)
+		}
+		if err := doc.CheckValid(); err != nil {
+			return nil, fmt.Errorf("can not load %q: %v", docURL, err)
+		}
+		c.docs[docURL] = doc
+	}
+	if ptr != "" && !strings.HasPrefix(ptr, "/") {
+		return nil, fmt.Errorf("unsupported $ref %q, only JSON Pointer fragment is supported", ref)
+	}
+	v, err := lookup(doc, ptr)
+	if err != nil {
+		return nil, fmt.Errorf("can not resolve $ref %q: %v", ref, err)
+	}
+	return c.compile(docURL, ptr, v)
+}
+
+// compile compiles the schema json at ptr of document base
+func (c *Compiler) compile(base, ptr string, json *jzon.JSON) (*Schema, error) {
+	location := base + "#" + ptr
+	if s, ok := c.schemas[location]; ok {
+		return s, nil
+	}
+	s, err := c.compileSchema(base, ptr, json)
+	if err != nil {
+		// do not keep the half compiled schema
+		delete(c.schemas, location)
+		return nil, err
+	}
+	return s, nil
+}
+
+func (c *Compiler) compileSchema(base, ptr string, json *jzon.JSON) (*Schema, error) {
+	location := base + "#" + ptr
+	s := &Schema{
+		location:      location,
+		minProperties: -1,
+		maxProperties: -1,
+		minItems:      -1,
+		maxItems:      -1,
+		minLength:     -1,
+		maxLength:     -1,
+	}
+	// register it before compiling subschemas for recursive $ref
+	c.schemas[location] = s
+
+	switch json.Kind() {
+	case jzon.Bool:
+		b, err := json.ParseBoolean()
+		if err != nil {
+			return nil, err
+		}
+		s.always = &b
+		return s, nil
+	case jzon.Object:
+	default:
+		return nil, &CompileError{Location: location, Err: errors.New("schema must be an object or a boolean")}
+	}
+
+	iter, err := json.UnsafeObject()
+	if err != nil {
+		return nil, err
+	}
+	for iter.Next() {
+		key := iter.Key()
+		if err := c.compileKeyword(s, base, ptr+"/"+escape(key), key, iter.Value()); err != nil {
+			return nil, wrapError(location+"/"+escape(key), err)
+		}
+	}
+	return s, iter.Err()
+}
+
+func (c *Compiler) compileKeyword(s *Schema, base, ptr, key string, v *jzon.JSON) error {
+	var err error
+	switch key {
+	case "$ref":
+		ref, err := v.ParseString()
+		if err != nil {
+			return err
+		}
+		c.refs = append(c.refs, pendingRef{schema: s, base: base, ref: ref})
+	case "type":
+		s.types, err = parseTypes(v)
+	case "enum":
+		s.enum, err = parseEnum(v)
+	case "const":
+		s.constant, err = v.InterfaceWith(jzon.InterfaceOptions{UseNumber: true})
+		s.hasConst = true
+	case "properties":
+		s.properties, s.propertyNames, err = c.compileMap(base, ptr, v)
+	case "required":
+		s.required, err = parseStrings(v)
+	case "additionalProperties":
+		s.additionalProperties, err = c.compile(base, ptr, v)
+	case "minProperties":
+		s.minProperties, err = parseCount(v)
+	case "maxProperties":
+		s.maxProperties, err = parseCount(v)
+	case "prefixItems":
+		s.prefixItems, err = c.compileList(base, ptr, v)
+	case "items":
+		s.items, err = c.compile(base, ptr, v)
+	case "minItems":
+		s.minItems, err = parseCount(v)
+	case "maxItems":
+		s.maxItems, err = parseCount(v)
+	case "pattern":
+		var p string
+		if p, err = v.ParseString(); err == nil {
+			s.pattern, err = regexp.Compile(p)
+		}
+	case "minLength":
+		s.minLength, err = parseCount(v)
+	case "maxLength":
+		s.maxLength, err = parseCount(v)
+	case "minimum":
+		s.minimum, err = parseNumber(v)
+	case "maximum":
+		s.maximum, err = parseNumber(v)
+	case "exclusiveMinimum":
+		s.exclusiveMinimum, err = parseNumber(v)
+	case "exclusiveMaximum":
+		s.exclusiveMaximum, err = parseNumber(v)
+	case "allOf":
+		s.allOf, err = c.compileList(base, ptr, v)
+	case "anyOf":
+		s.anyOf, err = c.compileList(base, ptr, v)
+	case "oneOf":
+		s.oneOf, err = c.compileList(base, ptr, v)
+	}
+	return err
+}
+
+// compileMap compiles an object of schemas, the names are in the order
+// as they appear in document
+func (c *Compiler) compileMap(base, ptr string, v *jzon.JSON) (map[string]*Schema, []string, error) {
+	if v.Kind() != jzon.Object {
+		return nil, nil, fmt.Errorf("must be an object")
+	}
+	iter, err := v.UnsafeObject()
+	if err != nil {
+		return nil, nil, err
+	}
+	schemas := make(map[string]*Schema)
+	var names []string
+	for iter.Next() {
+		key := iter.Key()
+		s, err := c.compile(base, ptr+"/"+escape(key), iter.Value())
+		if err != nil {
+			return nil, nil, err
+		}
+		if _, ok := schemas[key]; !ok {
+			names = append(names, key)
+		}
+		schemas[key] = s
+	}
+	return schemas, names, iter.Err()
+}
+
+// compileList compiles a non-empty array of schemas
+func (c *Compiler) compileList(base, ptr string, v *jzon.JSON) ([]*Schema, error) {
+	if v.Kind() != jzon.Array {
+		return nil, fmt.Errorf("must be an array")
+	}
+	iter, err := v.UnsafeArray()
+	if err != nil {
+		return nil, err
+	}
+	var list []*Schema
+	for iter.Next() {
+		s, err := c.compile(base, fmt.Sprintf("%s/%d", ptr, iter.Index()), iter.Value())
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, s)
+	}
+	if iter.Err() != nil {
+		return nil, iter.Err()
+	}
+	if len(list) == 0 {
+		return nil, fmt.Errorf("must not be empty")
+	}
+	return list, nil
+}
+
+var validTypes = map[string]bool{
+	"null": true, "boolean": true, "object": true, "array": true,
+	"number": true, "integer": true, "string": true,
+}
+
+func parseTypes(v *jzon.JSON) ([]string, error) {
+	var types []string
+	if v.Kind() == jzon.String {
+		t, err := v.ParseString()
+		if err != nil {
+			return nil, err
+		}
+		types = []string{t}
+	} else {
+		var err error
+		if types, err = parseStrings(v); err != nil {
+			return nil, err
+		}
+	}
+	for _, t := range types {
+		if !validTypes[t] {
+			return nil, fmt.Errorf("unknown type %q", t)
+		}
+	}
+	return types, nil
+}
+
+func parseStrings(v *jzon.JSON) ([]string, error) {
+	if v.Kind() != jzon.Array {
+		return nil, fmt.Errorf("must be an array of strings")
+	}
+	iter, err := v.UnsafeArray()
+	if err != nil {
+		return nil, err
+	}
+	var list []string
+	for iter.Next() {
+		s, err := iter.Value().ParseString()
+		if err != nil {
+			return nil, fmt.Errorf("must be an array of strings")
+		}
+		list = append(list, s)
+	}
+	return list, iter.Err()
+}
+
+func parseEnum(v *jzon.JSON) ([]interface{}, error) {
+	if v.Kind() != jzon.Array {
+		return nil, fmt.Errorf("must be an array")
+	}
+	enum, err := v.InterfaceWith(jzon.InterfaceOptions{UseNumber: true})
+	if err != nil {
+		return nil, err
+	}
+	return enum.([]interface{}), nil
+}
+
+// parseCount parses a non-negative integer
+func parseCount(v *jzon.JSON) (int, error) {
+	if v.Kind() != jzon.Number {
+		return 0, fmt.Errorf("must be a non-negative integer")
+	}
+	n, err := v.ParseInt64()
+	if err != nil || n < 0 || int64(int(n)) != n {
+		return 0, fmt.Errorf("must be a non-negative integer")
+	}
+	return int(n), nil
+}
+
+func parseNumber(v *jzon.JSON) (*big.Float, error) {
+	if v.Kind() != jzon.Number {
+		return nil, fmt.Errorf("must be a number")
+	}
+	return number(v)
+}
+
+// numberPrec is the precision of numbers, it is enough to compare
+// integers up to 77 digits exactly
+const numberPrec = 256
+
+// number parses the Number json value, the huge exponent is
+// rounded to zero or infinity
+func number(v *jzon.JSON) (*big.Float, error) {
+	f, _, err := big.ParseFloat(string(v.RawMessage()), 10, numberPrec, big.ToNearestEven)
+	if err != nil {
+		// the exponent overflows big.Float, ParseBigFloat rounds it
+		return v.ParseBigFloat()
+	}
+	return f, nil
+}
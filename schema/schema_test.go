@@ -0,0 +1,108 @@
+package schema
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/zoumo/jzon"
+)
+
+func TestCompile_Error(t *testing.T) {
+	tests := []struct {
+		name     string
+		schema   string
+		location string
+	}{
+		{"syntax", `{"type": }`, ""},
+		{"not schema", `1`, "#"},
+		{"unknown type", `{"type": "int"}`, "#/type"},
+		{"type not string", `{"type": [1]}`, "#/type"},
+		{"required", `{"required": "a"}`, "#/required"},
+		{"pattern", `{"pattern": "("}`, "#/pattern"},
+		{"negative count", `{"minItems": -1}`, "#/minItems"},
+		{"minimum", `{"minimum": "1"}`, "#/minimum"},
+		{"empty anyOf", `{"anyOf": []}`, "#/anyOf"},
+		{"nested", `{"properties": {"a/b": {"items": {"maxLength": 1.5}}}}`, "#/properties/a~1b/items/maxLength"},
+		{"missing ref", `{"$ref": "#/$defs/a"}`, "#/$ref"},
+		{"anchor ref", `{"$ref": "#a"}`, "#/$ref"},
+		{"remote ref", `{"$ref": "other.json"}`, "#/$ref"},
+		{"id", `{"$id": 1}`, "#/$id"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Compile(jzon.FromString(tt.schema))
+			if err == nil {
+				t.Fatalf("Compile() error = nil, want error")
+			}
+			if tt.location == "" {
+				return
+			}
+			var e *CompileError
+			if !errors.As(err, &e) {
+				t.Fatalf("Compile() error = %v, want CompileError", err)
+			}
+			if e.Location != tt.location {
+				t.Errorf("Compile() error location = %v, want %v", e.Location, tt.location)
+			}
+		})
+	}
+}
+
+func TestCompiler_Loader(t *testing.T) {
+	docs := map[string]string{
+		"https://example.com/user.json": `{
+			"type": "object",
+			"properties": {"name": {"$ref": "#/$defs/name"}, "group": {"$ref": "group.json"}},
+			"$defs": {"name": {"type": "string", "minLength": 1}}
+		}`,
+		"https://example.com/group.json": `{"enum": ["admin", "user"]}`,
+	}
+	var loads []string
+	c := &Compiler{Loader: LoaderFunc(func(url string) (*jzon.JSON, error) {
+		loads = append(loads, url)
+		doc, ok := docs[url]
+		if !ok {
+			return nil, errors.New("not found")
+		}
+		return jzon.FromString(doc), nil
+	})}
+
+	s, err := c.Compile(jzon.FromString(`{
+		"$id": "https://example.com/root.json",
+		"type": "array",
+		"items": {"$ref": "user.json"}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Location() != "https://example.com/root.json#" {
+		t.Errorf("Schema.Location() = %v", s.Location())
+	}
+	if want := []string{"https://example.com/user.json", "https://example.com/group.json"}; strings.Join(loads, " ") != strings.Join(want, " ") {
+		t.Errorf("Loader.Load() calls = %v, want %v", loads, want)
+	}
+
+	err = s.Validate(jzon.FromString(`[{"name": "zoumo", "group": "admin"}, {"name": "", "group": "guest"}]`))
+	got := violations(err)
+	want := []string{
+		`"/1/name": https://example.com/user.json#/$defs/name/minLength`,
+		`"/1/group": https://example.com/group.json#/enum`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Schema.Validate() = %v, want %v", got, want)
+	}
+
+	// the loaded documents are cached
+	if _, err := c.Compile(jzon.FromString(`{"$ref": "https://example.com/user.json"}`)); err != nil {
+		t.Fatal(err)
+	}
+	if len(loads) != 2 {
+		t.Errorf("Loader.Load() is called %d times, want 2", len(loads))
+	}
+
+	_, err = c.Compile(jzon.FromString(`{"$ref": "https://example.com/missing.json"}`))
+	if err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("Compile() error = %v, want not found", err)
+	}
+}
@@ -0,0 +1,436 @@
+package schema
+
+import (
+	stdjson "encoding/json"
+	"fmt"
+	"math/big"
+	"unicode/utf8"
+
+	"github.com/zoumo/jzon"
+)
+
+// Validate validates instance against the schema. If it does not match,
+// a *ValidationError which contains all violations is returned. If
+// instance is not a valid json document, the syntax error is returned.
+func (s *Schema) Validate(instance *jzon.JSON) error {
+	if err := instance.CheckComplete(); err != nil {
+		return err
+	}
+	v := &validator{refs: make(map[refKey]bool)}
+	if err := v.validate(s, "", instance.Clone()); err != nil {
+		return err
+	}
+	if len(v.violations) > 0 {
+		return &ValidationError{Violations: v.violations}
+	}
+	return nil
+}
+
+type refKey struct {
+	schema   *Schema
+	instance string
+}
+
+type validator struct {
+	violations []Violation
+	// refs are the $ref being followed, a $ref which is followed again
+	// without moving into instance is a loop
+	refs map[refKey]bool
+}
+
+func (v *validator) report(instance, location, format string, args ...interface{}) {
+	v.violations = append(v.violations, Violation{
+		InstanceLocation: instance,
+		SchemaLocation:   location,
+		Message:          fmt.Sprintf(format, args...),
+	})
+}
+
+// value is the json value being validated, the parsed forms are cached
+type value struct {
+	*jzon.JSON
+	kind  jzon.Kind
+	num   *big.Float
+	iface interface{}
+	ready bool
+}
+
+func (val *value) number() (*big.Float, error) {
+	if val.num == nil {
+		var err error
+		if val.num, err = number(val.JSON); err != nil {
+			return nil, err
+		}
+	}
+	return val.num, nil
+}
+
+func (val *value) typeName() (string, error) {
+	switch val.kind {
+	case jzon.Object:
+		return "object", nil
+	case jzon.Array:
+		return "array", nil
+	case jzon.String:
+		return "string", nil
+	case jzon.Bool:
+		return "boolean", nil
+	case jzon.Null:
+		return "null", nil
+	case jzon.Number:
+		// it is decided by the digits, big.Float rounds 1e-400 to 0
+		if val.IsInteger() {
+			return "integer", nil
+		}
+		return "number", nil
+	}
+	return "", fmt.Errorf("schema: can not validate %s JSON", val.kind)
+}
+
+func (val *value) materialized() (interface{}, error) {
+	if !val.ready {
+		var err error
+		val.iface, err = val.InterfaceWith(jzon.InterfaceOptions{UseNumber: true})
+		if err != nil {
+			return nil, err
+		}
+		val.ready = true
+	}
+	return val.iface, nil
+}
+
+func (v *validator) validate(s *Schema, loc string, json *jzon.JSON) error {
+	if s.always != nil {
+		if !*s.always {
+			v.report(loc, s.location, "no value is allowed")
+		}
+		return nil
+	}
+	val := &value{JSON: json, kind: json.Kind()}
+
+	if s.ref != nil {
+		key := refKey{s.ref, loc}
+		if v.refs[key] {
+			v.report(loc, s.location+"/$ref", "infinite $ref loop")
+		} else {
+			v.refs[key] = true
+			err := v.validate(s.ref, loc, json)
+			delete(v.refs, key)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := v.validateGeneric(s, loc, val); err != nil {
+		return err
+	}
+
+	var err error
+	switch val.kind {
+	case jzon.Object:
+		err = v.validateObject(s, loc, val)
+	case jzon.Array:
+		err = v.validateArray(s, loc, val)
+	case jzon.String:
+		err = v.validateString(s, loc, val)
+	case jzon.Number:
+		err = v.validateNumber(s, loc, val)
+	}
+	if err != nil {
+		return err
+	}
+
+	return v.validateCombinators(s, loc, json)
+}
+
+// validateGeneric validates type, enum and const
+func (v *validator) validateGeneric(s *Schema, loc string, val *value) error {
+	if len(s.types) > 0 {
+		name, err := val.typeName()
+		if err != nil {
+			return err
+		}
+		matched := false
+		for _, t := range s.types {
+			if t == name || t == "number" && name == "integer" {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			if len(s.types) == 1 {
+				v.report(loc, s.location+"/type", "expected %s, but got %s", s.types[0], name)
+			} else {
+				v.report(loc, s.location+"/type", "expected one of %v, but got %s", s.types, name)
+			}
+		}
+	}
+
+	if s.enum != nil {
+		i, err := val.materialized()
+		if err != nil {
+			return err
+		}
+		matched := false
+		for _, e := range s.enum {
+			if equal(i, e) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			v.report(loc, s.location+"/enum", "value is not one of the enum")
+		}
+	}
+
+	if s.hasConst {
+		i, err := val.materialized()
+		if err != nil {
+			return err
+		}
+		if !equal(i, s.constant) {
+			v.report(loc, s.location+"/const", "value is not equal to the const")
+		}
+	}
+	return nil
+}
+
+func (v *validator) validateObject(s *Schema, loc string, val *value) error {
+	if s.properties == nil && s.additionalProperties == nil && s.required == nil &&
+		s.minProperties < 0 && s.maxProperties < 0 {
+		return nil
+	}
+	iter, err := val.UnsafeObject()
+	if err != nil {
+		return err
+	}
+	seen := make(map[string]bool)
+	for iter.Next() {
+		key := iter.Key()
+		seen[key] = true
+		child := loc + "/" + escape(key)
+		if p, ok := s.properties[key]; ok {
+			if err := v.validate(p, child, iter.Value()); err != nil {
+				return err
+			}
+			continue
+		}
+		if a := s.additionalProperties; a != nil {
+			if a.always != nil && !*a.always {
+				v.report(loc, a.location, "additional property %q is not allowed", key)
+				continue
+			}
+			if err := v.validate(a, child, iter.Value()); err != nil {
+				return err
+			}
+		}
+	}
+	if iter.Err() != nil {
+		return iter.Err()
+	}
+
+	for _, name := range s.required {
+		if !seen[name] {
+			v.report(loc, s.location+"/required", "missing required property %q", name)
+		}
+	}
+	if s.minProperties >= 0 && len(seen) < s.minProperties {
+		v.report(loc, s.location+"/minProperties", "expected at least %d properties, but got %d", s.minProperties, len(seen))
+	}
+	if s.maxProperties >= 0 && len(seen) > s.maxProperties {
+		v.report(loc, s.location+"/maxProperties", "expected at most %d properties, but got %d", s.maxProperties, len(seen))
+	}
+	return nil
+}
+
+func (v *validator) validateArray(s *Schema, loc string, val *value) error {
+	if s.prefixItems == nil && s.items == nil && s.minItems < 0 && s.maxItems < 0 {
+		return nil
+	}
+	iter, err := val.UnsafeArray()
+	if err != nil {
+		return err
+	}
+	n := 0
+	for iter.Next() {
+		n++
+		i := iter.Index()
+		item := s.items
+		if i < len(s.prefixItems) {
+			item = s.prefixItems[i]
+		}
+		if item == nil {
+			continue
+		}
+		if err := v.validate(item, fmt.Sprintf("%s/%d", loc, i), iter.Value()); err != nil {
+			return err
+		}
+	}
+	if iter.Err() != nil {
+		return iter.Err()
+	}
+
+	if s.minItems >= 0 && n < s.minItems {
+		v.report(loc, s.location+"/minItems", "expected at least %d items, but got %d", s.minItems, n)
+	}
+	if s.maxItems >= 0 && n > s.maxItems {
+		v.report(loc, s.location+"/maxItems", "expected at most %d items, but got %d", s.maxItems, n)
+	}
+	return nil
+}
+
+func (v *validator) validateString(s *Schema, loc string, val *value) error {
+	if s.pattern == nil && s.minLength < 0 && s.maxLength < 0 {
+		return nil
+	}
+	str, err := val.ParseString()
+	if err != nil {
+		return err
+	}
+	n := utf8.RuneCountInString(str)
+	if s.minLength >= 0 && n < s.minLength {
+		v.report(loc, s.location+"/minLength", "expected length >= %d, but got %d", s.minLength, n)
+	}
+	if s.maxLength >= 0 && n > s.maxLength {
+		v.report(loc, s.location+"/maxLength", "expected length <= %d, but got %d", s.maxLength, n)
+	}
+	if s.pattern != nil && !s.pattern.MatchString(str) {
+		v.report(loc, s.location+"/pattern", "does not match pattern %q", s.pattern)
+	}
+	return nil
+}
+
+func (v *validator) validateNumber(s *Schema, loc string, val *value) error {
+	if s.minimum == nil && s.maximum == nil && s.exclusiveMinimum == nil && s.exclusiveMaximum == nil {
+		return nil
+	}
+	n, err := val.number()
+	if err != nil {
+		return err
+	}
+	if s.minimum != nil && n.Cmp(s.minimum) < 0 {
+		v.report(loc, s.location+"/minimum", "expected >= %v, but got %s", s.minimum, val.RawMessage())
+	}
+	if s.maximum != nil && n.Cmp(s.maximum) > 0 {
+		v.report(loc, s.location+"/maximum", "expected <= %v, but got %s", s.maximum, val.RawMessage())
+	}
+	if s.exclusiveMinimum != nil && n.Cmp(s.exclusiveMinimum) <= 0 {
+		v.report(loc, s.location+"/exclusiveMinimum", "expected > %v, but got %s", s.exclusiveMinimum, val.RawMessage())
+	}
+	if s.exclusiveMaximum != nil && n.Cmp(s.exclusiveMaximum) >= 0 {
+		v.report(loc, s.location+"/exclusiveMaximum", "expected < %v, but got %s", s.exclusiveMaximum, val.RawMessage())
+	}
+	return nil
+}
+
+// validateCombinators validates allOf, anyOf and oneOf, the violations
+// of subschemas are reported if anyOf or oneOf does not match
+func (v *validator) validateCombinators(s *Schema, loc string, json *jzon.JSON) error {
+	for _, sub := range s.allOf {
+		if err := v.validate(sub, loc, json); err != nil {
+			return err
+		}
+	}
+
+	if s.anyOf != nil {
+		var failed []Violation
+		matched := false
+		for _, sub := range s.anyOf {
+			violations, err := v.try(sub, loc, json)
+			if err != nil {
+				return err
+			}
+			if len(violations) == 0 {
+				matched = true
+				break
+			}
+			failed = append(failed, violations...)
+		}
+		if !matched {
+			v.report(loc, s.location+"/anyOf", "does not match any schema of anyOf")
+			v.violations = append(v.violations, failed...)
+		}
+	}
+
+	if s.oneOf != nil {
+		var failed []Violation
+		var matched []int
+		for i, sub := range s.oneOf {
+			violations, err := v.try(sub, loc, json)
+			if err != nil {
+				return err
+			}
+			if len(violations) == 0 {
+				matched = append(matched, i)
+			}
+			failed = append(failed, violations...)
+		}
+		switch len(matched) {
+		case 0:
+			v.report(loc, s.location+"/oneOf", "does not match any schema of oneOf")
+			v.violations = append(v.violations, failed...)
+		case 1:
+		default:
+			v.report(loc, s.location+"/oneOf", "matches schemas %v of oneOf, but only one is allowed", matched)
+		}
+	}
+	return nil
+}
+
+// try validates json against s and returns the violations
+// without reporting them
+func (v *validator) try(s *Schema, loc string, json *jzon.JSON) ([]Violation, error) {
+	sub := &validator{refs: v.refs}
+	if err := sub.validate(s, loc, json); err != nil {
+		return nil, err
+	}
+	return sub.violations, nil
+}
+
+// equal reports whether the values materialized with UseNumber are
+// equal, numbers are compared by value so 1 and 1.0 are equal
+func equal(a, b interface{}) bool {
+	switch a := a.(type) {
+	case nil:
+		return b == nil
+	case bool:
+		b, ok := b.(bool)
+		return ok && a == b
+	case string:
+		b, ok := b.(string)
+		return ok && a == b
+	case stdjson.Number:
+		b, ok := b.(stdjson.Number)
+		if !ok {
+			return false
+		}
+		x, err1 := number(jzon.FromString(string(a)))
+		y, err2 := number(jzon.FromString(string(b)))
+		return err1 == nil && err2 == nil && x.Cmp(y) == 0
+	case []interface{}:
+		b, ok := b.([]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if !equal(a[i], b[i]) {
+				return false
+			}
+		}
+		return true
+	case map[string]interface{}:
+		b, ok := b.(map[string]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for k, av := range a {
+			bv, ok := b[k]
+			if !ok || !equal(av, bv) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
@@ -0,0 +1,242 @@
+package schema
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/zoumo/jzon"
+)
+
+// violations returns the instance and schema locations of violations
+func violations(err error) []string {
+	var e *ValidationError
+	if !errors.As(err, &e) {
+		return nil
+	}
+	var list []string
+	for _, v := range e.Violations {
+		list = append(list, `"`+v.InstanceLocation+`": `+v.SchemaLocation)
+	}
+	return list
+}
+
+func TestSchema_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		schema   string
+		instance string
+		want     []string
+	}{
+		{"true", `true`, `{"a": 1}`, nil},
+		{"false", `false`, `1`, []string{`"": #`}},
+		{"type", `{"type": "string"}`, `1`, []string{`"": #/type`}},
+		{"types", `{"type": ["string", "null"]}`, `null`, nil},
+		{"integer", `{"type": "integer"}`, `1.0`, nil},
+		{"integer exponent", `{"type": "integer"}`, `1e2`, nil},
+		{"not integer", `{"type": "integer"}`, `1.5`, []string{`"": #/type`}},
+		{"number is integer", `{"type": "number"}`, `1`, nil},
+		{"enum", `{"enum": [1, "a", {"b": [null]}]}`, `{"b": [null]}`, nil},
+		{"enum number", `{"enum": [1, 2]}`, `2.0`, nil},
+		{"not enum", `{"enum": [1, "a"]}`, `"b"`, []string{`"": #/enum`}},
+		{"const", `{"const": [1, true]}`, `[1, false]`, []string{`"": #/const`}},
+		{"pattern", `{"pattern": "^a+$"}`, `"aab"`, []string{`"": #/pattern`}},
+		{"pattern not string", `{"pattern": "^a+$"}`, `1`, nil},
+		{"length", `{"minLength": 2, "maxLength": 3}`, `"中文"`, nil},
+		{"too short", `{"minLength": 2}`, `"中"`, []string{`"": #/minLength`}},
+		{"too long", `{"maxLength": 1}`, `"ab"`, []string{`"": #/maxLength`}},
+		{"minimum", `{"minimum": 1, "maximum": 2}`, `2`, nil},
+		{"below minimum", `{"minimum": 1}`, `0.5`, []string{`"": #/minimum`}},
+		{"above maximum", `{"maximum": 1}`, `1e1`, []string{`"": #/maximum`}},
+		{"exclusive", `{"exclusiveMinimum": 1, "exclusiveMaximum": 2}`, `2`, []string{`"": #/exclusiveMaximum`}},
+		{"big integer", `{"maximum": 9007199254740992}`, `9007199254740993`, []string{`"": #/maximum`}},
+		{"huge exponent", `{"maximum": 1}`, `1e999999999`, []string{`"": #/maximum`}},
+		{"huge exponent integer", `{"type": "integer"}`, `1e999999999`, nil},
+		{"exponent overflow", `{"minimum": 0}`, `-1e-99999999999`, nil},
+		{"exponent overflow exclusive", `{"exclusiveMinimum": 0}`, `-1e-99999999999`, []string{`"": #/exclusiveMinimum`}},
+		{"exponent overflow maximum", `{"maximum": 1}`, `1e99999999999`, []string{`"": #/maximum`}},
+		{"exponent overflow not integer", `{"type": "integer"}`, `1e-99999999999`, []string{`"": #/type`}},
+		{
+			"properties",
+			`{"properties": {"a": {"type": "string"}, "b/c": {"type": "integer"}}, "required": ["a", "d"]}`,
+			`{"a": 1, "b/c": 1.5, "e": null}`,
+			[]string{`"/a": #/properties/a/type`, `"/b~1c": #/properties/b~1c/type`, `"": #/required`},
+		},
+		{
+			"additionalProperties",
+			`{"properties": {"a": true}, "additionalProperties": {"type": "number"}}`,
+			`{"a": "x", "b": 1, "c": "y"}`,
+			[]string{`"/c": #/additionalProperties/type`},
+		},
+		{
+			"no additionalProperties",
+			`{"properties": {"a": true}, "additionalProperties": false}`,
+			`{"a": 1, "b": 2}`,
+			[]string{`"": #/additionalProperties`},
+		},
+		{"properties count", `{"minProperties": 1, "maxProperties": 1}`, `{}`, []string{`"": #/minProperties`}},
+		{
+			"items",
+			`{"prefixItems": [{"type": "string"}], "items": {"type": "integer"}, "maxItems": 2}`,
+			`["a", 1, "b"]`,
+			[]string{`"/2": #/items/type`, `"": #/maxItems`},
+		},
+		{"no items", `{"prefixItems": [{"type": "string"}], "items": false}`, `["a", 1]`, []string{`"/1": #/items`}},
+		{"minItems", `{"minItems": 1}`, `[]`, []string{`"": #/minItems`}},
+		{"allOf", `{"allOf": [{"type": "integer"}, {"minimum": 2}]}`, `1.5`, []string{`"": #/allOf/0/type`, `"": #/allOf/1/minimum`}},
+		{"anyOf", `{"anyOf": [{"type": "string"}, {"minimum": 2}]}`, `3`, nil},
+		{
+			"not anyOf",
+			`{"anyOf": [{"type": "string"}, {"minimum": 2}]}`,
+			`1`,
+			[]string{`"": #/anyOf`, `"": #/anyOf/0/type`, `"": #/anyOf/1/minimum`},
+		},
+		{"oneOf", `{"oneOf": [{"type": "string"}, {"type": "number", "minimum": 2}]}`, `"a"`, nil},
+		{"oneOf many", `{"oneOf": [{"type": "integer"}, {"minimum": 2}]}`, `3`, []string{`"": #/oneOf`}},
+		{"oneOf none", `{"oneOf": [{"type": "string"}]}`, `3`, []string{`"": #/oneOf`, `"": #/oneOf/0/type`}},
+		{
+			"ref",
+			`{"$defs": {"id": {"type": "integer", "minimum": 1}}, "properties": {"id": {"$ref": "#/$defs/id"}, "ids": {"items": {"$ref": "#/$defs/id"}}}}`,
+			`{"id": 0, "ids": [1, "2"]}`,
+			[]string{`"/id": #/$defs/id/minimum`, `"/ids/1": #/$defs/id/type`},
+		},
+		{
+			"recursive ref",
+			`{"type": "object", "properties": {"name": {"type": "string"}, "children": {"type": "array", "items": {"$ref": "#"}}}}`,
+			`{"name": "a", "children": [{"name": "b", "children": [{"name": 1}]}]}`,
+			[]string{`"/children/0/children/0/name": #/properties/name/type`},
+		},
+		{"ref with siblings", `{"$defs": {"a": {"type": "integer"}}, "$ref": "#/$defs/a", "minimum": 2}`, `1.5`, []string{`"": #/$defs/a/type`, `"": #/minimum`}},
+		{"ref loop", `{"$defs": {"a": {"$ref": "#/$defs/b"}, "b": {"$ref": "#/$defs/a"}}, "$ref": "#/$defs/a"}`, `1`, []string{`"": #/$defs/b/$ref`}},
+		{"escaped ref", `{"$defs": {"a~b": {"type": "null"}}, "$ref": "#/$defs/a~0b"}`, `1`, []string{`"": #/$defs/a~0b/type`}},
+		{"ref array", `{"prefixItems": [{"type": "null"}], "items": {"$ref": "#/prefixItems/0"}}`, `[null, 1]`, []string{`"/1": #/prefixItems/0/type`}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := Compile(jzon.FromString(tt.schema))
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = s.Validate(jzon.FromString(tt.instance))
+			if (err != nil) != (tt.want != nil) {
+				t.Fatalf("Schema.Validate() error = %v, want %v", err, tt.want)
+			}
+			got := violations(err)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Schema.Validate() = %q, want %q", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("Schema.Validate() = %q, want %q", got, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestSchema_Validate_Invalid(t *testing.T) {
+	s, err := Compile(jzon.FromString(`{"type": "object", "required": ["x"]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, data := range []string{`{"a": }`, `{"a": 1} junk`, `{"a": 1} {}`} {
+		err = s.Validate(jzon.FromString(data))
+		var e *ValidationError
+		if err == nil || errors.As(err, &e) {
+			t.Errorf("Schema.Validate(%s) error = %v, want syntax error", data, err)
+		}
+	}
+}
+
+func TestValidationError_Error(t *testing.T) {
+	s, err := Compile(jzon.FromString(`{"required": ["a"], "properties": {"b": {"type": "string"}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = s.Validate(jzon.FromString(`{"b": 1}`))
+	want := "schema: 2 violations\n" +
+		"\t\"/b\": expected string, but got integer (#/properties/b/type)\n" +
+		"\t\"\": missing required property \"a\" (#/required)"
+	if err == nil || err.Error() != want {
+		t.Errorf("ValidationError.Error() = %v, want %v", err, want)
+	}
+}
+
+func TestSchema_Validate_Inferred(t *testing.T) {
+	samples := []*jzon.JSON{
+		jzon.FromString(`{"id": 1, "role": "admin", "tags": ["a"], "email": null}`),
+		jzon.FromString(`{"id": 2, "role": "user", "tags": [], "email": "b@example.com"}`),
+		jzon.FromString(`{"id": 3, "role": "user", "tags": ["b", "c"]}`),
+		jzon.FromString(`{"id": 4, "role": "admin", "tags": ["c"]}`),
+	}
+	doc, err := jzon.InferSchema(samples...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := Compile(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, sample := range samples {
+		if err := s.Validate(sample); err != nil {
+			t.Errorf("Schema.Validate() of sample %d error = %v", i, err)
+		}
+	}
+	got := violations(s.Validate(jzon.FromString(`{"id": 1.5, "role": "guest", "tags": [1]}`)))
+	want := []string{`"/id": #/properties/id/type`, `"/role": #/properties/role/enum`, `"/tags/0": #/properties/tags/items/type`}
+	if len(got) != len(want) {
+		t.Fatalf("Schema.Validate() = %q, want %q", got, want)
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Errorf("Schema.Validate() = %q, want %q", got, want)
+			break
+		}
+	}
+}
+
+func TestSchema_Validate_InferredNumbers(t *testing.T) {
+	tests := []struct {
+		samples []string
+		// invalid are the values which do not match the inferred schema
+		invalid []string
+	}{
+		{
+			[]string{`1e400`, `-1e400`, `9007199254740993.5`, `9007199254740993`, `10.0`, `9223372036854775808`},
+			[]string{`1e401`, `-1e401`, `"1"`},
+		},
+		{
+			// the inferred integers are integers in validation
+			[]string{`10.0`, `1e400`, `-1e999999999`, `9223372036854775808`},
+			[]string{`1.5`, `1e-99999999999`, `1e1000000000`},
+		},
+		{
+			[]string{`-1e-99999999999`, `1e-5`},
+			[]string{`1e-4`},
+		},
+	}
+	for _, tt := range tests {
+		var samples []*jzon.JSON
+		for _, s := range tt.samples {
+			samples = append(samples, jzon.FromString(s))
+		}
+		doc, err := jzon.InferSchema(samples...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s, err := Compile(doc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, sample := range samples {
+			if err := s.Validate(sample); err != nil {
+				t.Errorf("Schema.Validate(%v) of %v error = %v", sample, doc, err)
+			}
+		}
+		for _, data := range tt.invalid {
+			if s.Validate(jzon.FromString(data)) == nil {
+				t.Errorf("Schema.Validate(%s) of %v error = nil, want error", data, doc)
+			}
+		}
+	}
+}
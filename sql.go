@@ -0,0 +1,70 @@
+package jzon
+
+import (
+	"database/sql/driver"
+	"fmt"
+)
+
+// NullJSON represents a JSON column that may be null, it implements
+// sql.Scanner and driver.Valuer, so it can be used as the destination of
+// Scan and the argument of Exec directly.
+//
+//	var doc jzon.NullJSON
+//	err := db.QueryRow("SELECT doc FROM t WHERE id = ?", 1).Scan(&doc)
+//	if doc.Valid {
+//		name := doc.JSON.GetString("", "user", "name")
+//	}
+type NullJSON struct {
+	JSON *JSON
+	// Valid is true if JSON is not NULL
+	Valid bool
+}
+
+// Scan implements sql.Scanner, the value is copied and checked strictly,
+// only whitespaces are allowed after the JSON value. The settings of existing n.JSON, like Limits and
+// DuplicateKeyPolicy, are applied to the scanned value.
+func (n *NullJSON) Scan(value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		n.Valid = false
+		if n.JSON != nil {
+			n.JSON = n.JSON.sub(nil)
+		}
+		return nil
+	case []byte:
+		// the driver may reuse the buffer after Scan
+		data = make([]byte, len(v))
+		copy(data, v)
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("jzon: can not scan %T into NullJSON", value)
+	}
+
+	var json *JSON
+	if n.JSON != nil {
+		json = n.JSON.sub(data)
+	} else {
+		json = FromBytes(data)
+	}
+	if err := json.CheckComplete(); err != nil {
+		return err
+	}
+	n.JSON = json
+	n.Valid = true
+	return nil
+}
+
+// Value implements driver.Valuer, it returns the underlying bytes of
+// JSON value after checking it strictly, or nil if it is not Valid
+func (n NullJSON) Value() (driver.Value, error) {
+	if !n.Valid || n.JSON == nil {
+		return nil, nil
+	}
+	raw, err := n.JSON.appendRaw(nil)
+	if err != nil {
+		return nil, err
+	}
+	return raw, nil
+}
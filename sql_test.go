@@ -0,0 +1,195 @@
+package jzon
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"testing"
+)
+
+// fakeDriver is a database/sql driver which returns rows of a single
+// column, the values are set by the test, and records the args of Exec
+type fakeDriver struct {
+	rows []driver.Value
+	args []driver.Value
+}
+
+func (d *fakeDriver) Open(name string) (driver.Conn, error) {
+	return &fakeConn{d}, nil
+}
+
+type fakeConn struct {
+	d *fakeDriver
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeStmt{c.d}, nil
+}
+
+func (c *fakeConn) Close() error {
+	return nil
+}
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("fake: transaction is not supported")
+}
+
+type fakeStmt struct {
+	d *fakeDriver
+}
+
+func (s *fakeStmt) Close() error {
+	return nil
+}
+
+func (s *fakeStmt) NumInput() int {
+	return -1
+}
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.d.args = args
+	return driver.RowsAffected(1), nil
+}
+
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return &fakeRows{rows: s.d.rows}, nil
+}
+
+type fakeRows struct {
+	rows []driver.Value
+	i    int
+}
+
+func (r *fakeRows) Columns() []string {
+	return []string{"doc"}
+}
+
+func (r *fakeRows) Close() error {
+	return nil
+}
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.i >= len(r.rows) {
+		return io.EOF
+	}
+	dest[0] = r.rows[r.i]
+	r.i++
+	return nil
+}
+
+var fake = &fakeDriver{}
+
+func init() {
+	sql.Register("jzonfake", fake)
+}
+
+func TestNullJSON_Scan(t *testing.T) {
+	db, err := sql.Open("jzonfake", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	fake.rows = []driver.Value{
+		[]byte(`{"user": {"name": "zoumo", "id": 1}}`),
+		nil,
+		`[1, 2, 3]`,
+		[]byte(`{"a": `),
+		int64(1),
+		[]byte(`[1,2] junk`),
+	}
+	rows, err := db.Query("SELECT doc FROM t")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	var docs []NullJSON
+	var errs []error
+	for rows.Next() {
+		var doc NullJSON
+		err := rows.Scan(&doc)
+		docs = append(docs, doc)
+		errs = append(errs, err)
+	}
+	if len(docs) != 6 {
+		t.Fatalf("scanned %d rows, want 6", len(docs))
+	}
+
+	if errs[0] != nil || !docs[0].Valid || docs[0].JSON.GetString("", "user", "name") != "zoumo" {
+		t.Errorf("row 0 = %v %v", docs[0], errs[0])
+	}
+	if errs[1] != nil || docs[1].Valid {
+		t.Errorf("row 1 = %v %v, want NULL", docs[1], errs[1])
+	}
+	if errs[2] != nil || !docs[2].Valid || docs[2].JSON.GetInt(0, 2) != 3 {
+		t.Errorf("row 2 = %v %v", docs[2], errs[2])
+	}
+	if errs[3] == nil || docs[3].Valid {
+		t.Errorf("row 3 = %v %v, want error", docs[3], errs[3])
+	}
+	if errs[4] == nil || docs[4].Valid {
+		t.Errorf("row 4 = %v %v, want error", docs[4], errs[4])
+	}
+	if errs[5] == nil || docs[5].Valid {
+		t.Errorf("row 5 = %v %v, want error", docs[5], errs[5])
+	}
+}
+
+func TestNullJSON_Scan_Settings(t *testing.T) {
+	doc := NullJSON{JSON: new(JSON).SetDuplicateKeyPolicy(DuplicateKeyReject)}
+	if err := doc.Scan(`{"a": 1, "a": 2}`); err == nil {
+		t.Errorf("NullJSON.Scan() with Reject error = nil, want error")
+	}
+
+	doc = NullJSON{JSON: new(JSON).SetLimits(Limits{MaxDocumentSize: 4})}
+	if err := doc.Scan(`[1, 2]`); err == nil {
+		t.Errorf("NullJSON.Scan() with limits error = nil, want error")
+	}
+	if err := doc.Scan(nil); err != nil || doc.Valid {
+		t.Errorf("NullJSON.Scan(nil) = %v %v", doc, err)
+	}
+	if err := doc.Scan(`[1]`); err != nil || !doc.Valid {
+		t.Errorf("NullJSON.Scan() = %v %v", doc, err)
+	}
+
+	// the buffer of driver is copied
+	buf := []byte(`[1]`)
+	if err := doc.Scan(buf); err != nil {
+		t.Fatal(err)
+	}
+	buf[1] = '2'
+	if got := doc.JSON.GetInt(0, 0); got != 1 {
+		t.Errorf("NullJSON.JSON.GetInt(0) = %v, want 1", got)
+	}
+}
+
+func TestNullJSON_Value(t *testing.T) {
+	db, err := sql.Open("jzonfake", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	json := FromString(`{"a": {"b": [1, 2]}}`)
+	if err := json.Path("a"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec("INSERT INTO t VALUES (?, ?)", NullJSON{JSON: json, Valid: true}, NullJSON{}); err != nil {
+		t.Fatal(err)
+	}
+	if len(fake.args) != 2 {
+		t.Fatalf("Exec args = %v, want 2 args", fake.args)
+	}
+	if got, ok := fake.args[0].([]byte); !ok || string(got) != `{"b": [1, 2]}` {
+		t.Errorf("NullJSON.Value() = %v, want {\"b\": [1, 2]}", fake.args[0])
+	}
+	if fake.args[1] != nil {
+		t.Errorf("NullJSON{}.Value() = %v, want nil", fake.args[1])
+	}
+
+	if v, err := (NullJSON{JSON: FromString(`[1,2] junk`), Valid: true}).Value(); err == nil {
+		t.Errorf("NullJSON.Value() = %s, want error", v)
+	}
+}
@@ -0,0 +1,122 @@
+package jzon
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+// ScanMode defines the backend used by scanners to walk through bytes
+type ScanMode uint
+
+const (
+	// ScanByteWise walks through the data byte by byte
+	ScanByteWise ScanMode = iota
+	// ScanSWAR processes 8 bytes at a time using SWAR (SIMD within a register)
+	// word tricks to locate quotes, backslashes and structural characters.
+	// The results are identical to ScanByteWise.
+	ScanSWAR
+)
+
+func (m ScanMode) String() string {
+	switch m {
+	case ScanByteWise:
+		return "ByteWise"
+	case ScanSWAR:
+		return "SWAR"
+	default:
+		return "Unkown"
+	}
+}
+
+// SetScanMode sets the backend used by scanners,
+// and inherited by the iterators created from json.
+func (json *JSON) SetScanMode(mode ScanMode) *JSON {
+	json.scanMode = mode
+	return json
+}
+
+const (
+	swarLSB  = 0x0101010101010101
+	swarMSB  = 0x8080808080808080
+	swarLow7 = 0x7f7f7f7f7f7f7f7f
+)
+
+// swarLoad loads 8 bytes from b, the first byte is the lowest byte of word
+func swarLoad(b []byte) uint64 {
+	return binary.LittleEndian.Uint64(b)
+}
+
+// swarEqual sets the high bit of every byte in x which is equal to c.
+// The low 7 bits of each byte are added separately, so there is no
+// carry across bytes and the result is exact.
+func swarEqual(x uint64, c byte) uint64 {
+	y := x ^ (swarLSB * uint64(c))
+	return ^(((y & swarLow7) + swarLow7) | y) & swarMSB
+}
+
+// swarLess sets the high bit of every byte in x which is less than c,
+// c must not be greater than 128.
+func swarLess(x uint64, c byte) uint64 {
+	return ^(((x & swarLow7) + swarLSB*uint64(0x80-c)) | x) & swarMSB
+}
+
+// swarFirst returns the index of the byte marked by the lowest set bit
+func swarFirst(mask uint64) int {
+	return bits.TrailingZeros64(mask) >> 3
+}
+
+// swarStringSpecial returns the index of the first byte in data[i:n]
+// which is a quote, a backslash or a control character, or n if not found
+func swarStringSpecial(data []byte, i, n int) int {
+	for ; i+8 <= n; i += 8 {
+		x := swarLoad(data[i:])
+		mask := swarEqual(x, '"') | swarEqual(x, '\\') | swarLess(x, ' ')
+		if mask != 0 {
+			return i + swarFirst(mask)
+		}
+	}
+	for ; i < n; i++ {
+		if c := data[i]; c == '"' || c == '\\' || c < ' ' {
+			return i
+		}
+	}
+	return n
+}
+
+// swarSkipSpace returns the index of the first byte in data[i:n]
+// which is not a whitespace, or n if not found
+func swarSkipSpace(data []byte, i, n int) int {
+	for ; i+8 <= n; i += 8 {
+		x := swarLoad(data[i:])
+		space := swarEqual(x, ' ') | swarEqual(x, '\n') | swarEqual(x, '\t') | swarEqual(x, '\r')
+		if space != swarMSB {
+			return i + swarFirst(^space&swarMSB)
+		}
+	}
+	for ; i < n; i++ {
+		switch data[i] {
+		case ' ', '\n', '\t', '\r':
+			continue
+		}
+		return i
+	}
+	return n
+}
+
+// swarFind returns the index of the first byte in data[i:n]
+// which is equal to a, b or c, or n if not found
+func swarFind(data []byte, i, n int, a, b, c byte) int {
+	for ; i+8 <= n; i += 8 {
+		x := swarLoad(data[i:])
+		mask := swarEqual(x, a) | swarEqual(x, b) | swarEqual(x, c)
+		if mask != 0 {
+			return i + swarFirst(mask)
+		}
+	}
+	for ; i < n; i++ {
+		if d := data[i]; d == a || d == b || d == c {
+			return i
+		}
+	}
+	return n
+}
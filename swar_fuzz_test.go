@@ -0,0 +1,19 @@
+//go:build go1.18
+// +build go1.18
+
+package jzon
+
+import "testing"
+
+func FuzzScanMode(f *testing.F) {
+	for _, data := range scanCorpus {
+		f.Add(data)
+	}
+	f.Fuzz(func(t *testing.T, data string) {
+		want := scanAll(data, ScanByteWise)
+		got := scanAll(data, ScanSWAR)
+		if got != want {
+			t.Errorf("scan(%q) with SWAR = %+v, want %+v", data, got, want)
+		}
+	})
+}
@@ -0,0 +1,124 @@
+package jzon
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+var scanCorpus = []string{
+	``, ` `, `[]`, `{}`, `"a"`, `"abcdefghijklmnopqrstuvwxyz"`, `"abc\"def\\ghiéjkl"`,
+	`"abcdefgh` + "\x01" + `"`, `"中文字符串，很长很长很长"`, `"unterminated string......`,
+	`[1,2,3]`, `{"a":1}`, `[[1,2], [3,4]]`, `{"a":{"b":[{}, []]}}`, jsonStr,
+	`[`, `{`, `[1,]`, `[1 2]`, `{"a":1 x}`, `[tru]`, `[01]`, `["\x"]`,
+	`        [          1          ,          2          ]          `,
+	"\t\t\t\t\t\t\t\t\n\n\n\n\n\n\n\n{}\r\r\r\r\r\r\r\r!",
+	`{"key with [brackets] and {braces}": ["]]]]]]]]", "}}}}}}}}"]}`,
+	`{"a":"\u12","b":1}`, `["abc\` + "\n" + `"]`, `{"":"`,
+}
+
+// scanResult is the result of all scanners on data
+type scanResult struct {
+	token       byte
+	tokenOffset int
+	validEnd    int
+	unsafeEnd   int
+	checkErr    string
+	indexErr    string
+}
+
+func scanAll(data string, mode ScanMode) scanResult {
+	var r scanResult
+	j := FromString(data).SetScanMode(mode)
+	r.token, _ = j.nextToken()
+	r.tokenOffset = j.offset
+
+	j = FromString(data).SetScanMode(mode)
+	r.validEnd, _ = j.validValueEnd()
+
+	j = FromString(data).SetScanMode(mode)
+	r.unsafeEnd, _ = j.unsafeValueEnd()
+
+	r.checkErr = errString(FromString(data).SetScanMode(mode).CheckValid())
+	r.indexErr = errString(FromString(data).SetScanMode(mode).BuildIndex())
+	return r
+}
+
+func TestScanMode_Corpus(t *testing.T) {
+	for i, data := range scanCorpus {
+		want := scanAll(data, ScanByteWise)
+		got := scanAll(data, ScanSWAR)
+		if got != want {
+			t.Errorf("%d: scan(%q) with SWAR = %+v, want %+v", i, data, got, want)
+		}
+	}
+}
+
+func TestSwarEqual(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	buf := make([]byte, 8)
+	for n := 0; n < 10000; n++ {
+		r.Read(buf)
+		c := byte(r.Intn(256))
+		// make matches more likely
+		buf[r.Intn(8)] = c
+		buf[r.Intn(8)] = c ^ 1
+		mask := swarEqual(swarLoad(buf), c)
+		for i, b := range buf {
+			if got, want := mask&(0x80<<(uint(i)*8)) != 0, b == c; got != want {
+				t.Fatalf("swarEqual(%v, %v) byte %d = %v, want %v", buf, c, i, got, want)
+			}
+		}
+	}
+}
+
+func TestSwarLess(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	buf := make([]byte, 8)
+	for n := 0; n < 10000; n++ {
+		r.Read(buf)
+		c := byte(r.Intn(129))
+		mask := swarLess(swarLoad(buf), c)
+		for i, b := range buf {
+			if got, want := mask&(0x80<<(uint(i)*8)) != 0, b < c; got != want {
+				t.Fatalf("swarLess(%v, %v) byte %d = %v, want %v", buf, c, i, got, want)
+			}
+		}
+	}
+}
+
+func TestSwarSkipSpace(t *testing.T) {
+	tests := []struct {
+		data string
+		want int
+	}{
+		{"", 0},
+		{"        ", 8},
+		{"         x", 9},
+		{" \t\r\n \t\r\n!", 8},
+		{"x", 0},
+		{"   \x21    ", 3},
+	}
+	for _, tt := range tests {
+		if got := swarSkipSpace([]byte(tt.data), 0, len(tt.data)); got != tt.want {
+			t.Errorf("swarSkipSpace(%q) = %v, want %v", tt.data, got, tt.want)
+		}
+	}
+}
+
+func benchmarkScan(b *testing.B, mode ScanMode) {
+	data := []byte(`[` + strings.Repeat(`{"name": "a long string value without escapes", "list": [1, 2, 3], "o": {"k": "v"}},`, 100) + `{}]`)
+	b.SetBytes(int64(len(data)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		FromBytes(data).SetScanMode(mode).unsafeValueEnd()
+	}
+}
+
+func BenchmarkJSON_unsafeValueEnd_ByteWise(b *testing.B) {
+	benchmarkScan(b, ScanByteWise)
+}
+
+func BenchmarkJSON_unsafeValueEnd_SWAR(b *testing.B) {
+	benchmarkScan(b, ScanSWAR)
+}
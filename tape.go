@@ -0,0 +1,227 @@
+package jzon
+
+import (
+	"fmt"
+	"sort"
+)
+
+// node describes one value of the JSON document in tape
+type node struct {
+	kind Kind
+	// head and tail are the index of value in JSON.data
+	head, tail int
+	// keyHead and keyTail are the index of quoted key
+	// if the value is a member of object, otherwise -1
+	keyHead, keyTail int
+	// children of object and array are tape.children[first : first+count]
+	first, count int
+	// keys maps the unquoted keys of object to its members
+	keys map[string]*tapeKey
+	// dup is the first duplicate key of object, it is nil if no key
+	// is duplicated
+	dup *DuplicateKeyError
+}
+
+// tapeKey is the members of object with the same key
+type tapeKey struct {
+	// first and last are the node indexes of the first and last member
+	first, last int
+}
+
+// tape is a structural index of JSON document, it records the position
+// of every value, so the document does not need to be scanned again.
+type tape struct {
+	// nodes are sorted by head, the order is the same as they appear in document
+	nodes []node
+	// children contains the node indexes, the children of
+	// an object or array are contiguous
+	children []int
+}
+
+// tapeBuilder builds tape while validBlockEnd checks the document
+type tapeBuilder struct {
+	tape *tape
+	// open is the stack of open object and array
+	open []int
+	// pending is the stack of children which are not stored in tape
+	pending []int
+	// marks are the start of children in pending for every open node
+	marks []int
+	// keyHead and keyTail are the key of next value
+	keyHead, keyTail int
+}
+
+func newTapeBuilder() *tapeBuilder {
+	return &tapeBuilder{
+		tape:    &tape{},
+		keyHead: -1,
+		keyTail: -1,
+	}
+}
+
+func (b *tapeBuilder) key(head, tail int) {
+	b.keyHead = head
+	b.keyTail = tail
+}
+
+func (b *tapeBuilder) add(kind Kind, head, tail int) int {
+	b.tape.nodes = append(b.tape.nodes, node{
+		kind:    kind,
+		head:    head,
+		tail:    tail,
+		keyHead: b.keyHead,
+		keyTail: b.keyTail,
+	})
+	b.keyHead, b.keyTail = -1, -1
+	i := len(b.tape.nodes) - 1
+	b.pending = append(b.pending, i)
+	return i
+}
+
+// value adds a value which is not object or array
+func (b *tapeBuilder) value(kind Kind, head, tail int) {
+	b.add(kind, head, tail)
+}
+
+// push adds an object or array which starts at head
+func (b *tapeBuilder) push(kind Kind, head int) {
+	i := b.add(kind, head, -1)
+	b.open = append(b.open, i)
+	b.marks = append(b.marks, len(b.pending))
+}
+
+// pop closes the innermost object or array at tail
+func (b *tapeBuilder) pop(tail int) {
+	i := b.open[len(b.open)-1]
+	mark := b.marks[len(b.marks)-1]
+	b.open = b.open[:len(b.open)-1]
+	b.marks = b.marks[:len(b.marks)-1]
+
+	n := &b.tape.nodes[i]
+	n.tail = tail
+	n.first = len(b.tape.children)
+	n.count = len(b.pending) - mark
+	b.tape.children = append(b.tape.children, b.pending[mark:]...)
+	b.pending = b.pending[:mark]
+}
+
+// BuildIndex checks the value represented by json strictly and builds
+// a structural index of it in one pass. After that ObjectIndex, Index,
+// Path and the Len of iterators look up the index instead of scanning
+// the document again, which is efficient for repeated random access.
+// Looking up a key of object is O(1) with the index, so Path is
+// O(depth).
+func (json *JSON) BuildIndex() error {
+	now := json.offset
+	defer func() {
+		json.offset = now
+	}()
+	json.offset = json.head
+
+	if !json.checkDocumentSize() {
+		return json.err
+	}
+
+	json.builder = newTapeBuilder()
+	defer func() {
+		json.builder = nil
+	}()
+
+	json.Predict()
+	head := json.offset
+	end, kind := json.validValueEnd()
+	if end == -1 {
+		return json.err
+	}
+	if kind != Object && kind != Array {
+		json.builder.value(kind, head, end)
+	}
+	json.tape = json.builder.tape
+	json.tape.indexKeys(json.data)
+	return nil
+}
+
+// indexKeys builds the key map of every object, so looking up a key
+// in object is O(1)
+func (t *tape) indexKeys(data []byte) {
+	for i := range t.nodes {
+		n := &t.nodes[i]
+		if n.kind != Object || n.count == 0 {
+			continue
+		}
+		n.keys = make(map[string]*tapeKey, n.count)
+		for _, c := range t.children[n.first : n.first+n.count] {
+			child := &t.nodes[c]
+			k, _ := unquote(data[child.keyHead:child.keyTail])
+			if key, ok := n.keys[k]; ok {
+				if n.dup == nil {
+					n.dup = &DuplicateKeyError{k, t.nodes[key.first].keyHead, child.keyHead}
+				}
+				key.last = c
+				continue
+			}
+			n.keys[k] = &tapeKey{first: c, last: c}
+		}
+	}
+}
+
+// node returns the node of value at json.offset in tape
+func (json *JSON) node() (*node, bool) {
+	if json.tape == nil {
+		return nil, false
+	}
+	if _, ok := json.nextToken(); !ok {
+		return nil, false
+	}
+	nodes := json.tape.nodes
+	// the cursor is the last node found by tapeObjectIndex or tapeIndex,
+	// it makes Path O(depth)
+	if c := json.cursor - 1; c >= 0 && c < len(nodes) && nodes[c].head == json.offset {
+		return &nodes[c], true
+	}
+	i := sort.Search(len(nodes), func(i int) bool {
+		return nodes[i].head >= json.offset
+	})
+	if i == len(nodes) || nodes[i].head != json.offset {
+		return nil, false
+	}
+	return &nodes[i], true
+}
+
+// tapeObjectIndex is like ObjectIndex, but looks up the tape
+func (json *JSON) tapeObjectIndex(n *node, key string) error {
+	if json.dupPolicy == DuplicateKeyReject && n.dup != nil {
+		json.err = *n.dup
+		return json.err
+	}
+	k, ok := n.keys[key]
+	if !ok {
+		json.err = fmt.Errorf("object: key[%s] not found", key)
+		return json.err
+	}
+	if json.dupPolicy == DuplicateKeyLastWins {
+		json.moveToNode(k.last)
+	} else {
+		json.moveToNode(k.first)
+	}
+	return nil
+}
+
+// tapeIndex is like Index, but looks up the tape
+func (json *JSON) tapeIndex(n *node, index int) error {
+	if index < 0 || index >= n.count {
+		json.err = fmt.Errorf("array: index[%d] out of range", index)
+		return json.err
+	}
+	json.moveToNode(json.tape.children[n.first+index])
+	return nil
+}
+
+// moveToNode moves json to the value of node i
+func (json *JSON) moveToNode(i int) {
+	n := &json.tape.nodes[i]
+	json.head = n.head
+	json.tail = n.tail
+	json.offset = n.head
+	json.cursor = i + 1
+}
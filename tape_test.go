@@ -0,0 +1,171 @@
+package jzon
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestJSON_BuildIndex(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr bool
+	}{
+		{"1", jsonStr, false},
+		{"2", `[1, "2", [3], {"4": 4}]`, false},
+		{"3", ` "string" `, false},
+		{"4", `123`, false},
+		{"5", `{"a": [1, 2}`, true},
+		{"6", ``, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			json := FromString(tt.data)
+			err := json.BuildIndex()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("JSON.BuildIndex() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if (json.tape == nil) != tt.wantErr {
+				t.Errorf("JSON.BuildIndex() tape = %v, wantErr %v", json.tape, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestJSON_BuildIndex_Path(t *testing.T) {
+	paths := [][]interface{}{
+		{"string"},
+		{"number2"},
+		{"object", "k2", 2},
+		{"object", "o2", "k1"},
+		{"object", "o"},
+		{"list", 1, "values", 3},
+		{"list", 0, "name"},
+		{"list", 2},
+		{"object", "k2", -1},
+		{"missing"},
+		{"list", "name"},
+	}
+	indexed := FromString(jsonStr)
+	if err := indexed.BuildIndex(); err != nil {
+		t.Fatalf("JSON.BuildIndex() error = %v", err)
+	}
+	for i, path := range paths {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			want := FromString(jsonStr)
+			wantErr := want.Path(path...)
+
+			got := *indexed
+			gotErr := got.Path(path...)
+			if (gotErr != nil) != (wantErr != nil) {
+				t.Fatalf("JSON.Path(%v) error = %v, want %v", path, gotErr, wantErr)
+			}
+			if wantErr != nil {
+				return
+			}
+			if got.String() != want.String() {
+				t.Errorf("JSON.Path(%v) = %v, want %v", path, got.String(), want.String())
+			}
+		})
+	}
+}
+
+func TestJSON_BuildIndex_Len(t *testing.T) {
+	json := FromString(jsonStr)
+	if err := json.BuildIndex(); err != nil {
+		t.Fatalf("JSON.BuildIndex() error = %v", err)
+	}
+	obj, _ := json.Object()
+	if got := obj.Len(); got != 7 {
+		t.Errorf("ObjectIter.Len() = %v, want %v", got, 7)
+	}
+	if err := json.Path("list", 0, "values"); err != nil {
+		t.Fatalf("JSON.Path() error = %v", err)
+	}
+	arr, _ := json.Array()
+	if got := arr.Len(); got != 4 {
+		t.Errorf("ArrayIter.Len() = %v, want %v", got, 4)
+	}
+}
+
+func TestJSON_BuildIndex_DuplicateKey(t *testing.T) {
+	data := `{"k": 1, "k": 2}`
+	tests := []struct {
+		name    string
+		policy  DuplicateKeyPolicy
+		want    string
+		wantErr bool
+	}{
+		{"allow", DuplicateKeyAllow, "1", false},
+		{"first", DuplicateKeyFirstWins, "1", false},
+		{"last", DuplicateKeyLastWins, "2", false},
+		{"reject", DuplicateKeyReject, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			json := FromString(data).SetDuplicateKeyPolicy(tt.policy)
+			err := json.BuildIndex()
+			if err == nil {
+				err = json.ObjectIndex("k")
+			}
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("JSON.ObjectIndex() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && json.String() != tt.want {
+				t.Errorf("JSON.ObjectIndex() = %v, want %v", json.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestJSON_BuildIndex_Keys(t *testing.T) {
+	json := FromString(`{"a\u0062": 1, "k": 2, "x": {}, "k": 3, "k": 4, "c": {"d": 5}}`)
+	if err := json.BuildIndex(); err != nil {
+		t.Fatalf("JSON.BuildIndex() error = %v", err)
+	}
+	tests := []struct {
+		policy DuplicateKeyPolicy
+		keys   []interface{}
+		want   string
+	}{
+		{DuplicateKeyAllow, []interface{}{"ab"}, "1"},
+		{DuplicateKeyAllow, []interface{}{"k"}, "2"},
+		{DuplicateKeyLastWins, []interface{}{"k"}, "4"},
+		{DuplicateKeyAllow, []interface{}{"c", "d"}, "5"},
+		{DuplicateKeyAllow, []interface{}{"x", "y"}, ""},
+		{DuplicateKeyAllow, []interface{}{"a\\u0062"}, ""},
+		// the policy is applied after the index is built
+		{DuplicateKeyReject, []interface{}{"ab"}, ""},
+	}
+	for _, tt := range tests {
+		got := json.Clone().SetDuplicateKeyPolicy(tt.policy)
+		err := got.Path(tt.keys...)
+		if (err != nil) != (tt.want == "") {
+			t.Fatalf("JSON.Path(%v) with policy %v error = %v", tt.keys, tt.policy, err)
+		}
+		if err == nil && got.String() != tt.want {
+			t.Errorf("JSON.Path(%v) with policy %v = %v, want %v", tt.keys, tt.policy, got, tt.want)
+		}
+	}
+	err := json.Clone().SetDuplicateKeyPolicy(DuplicateKeyReject).Path("ab")
+	if e, ok := err.(DuplicateKeyError); !ok || e.Key != "k" || e.First != 15 || e.Second != 32 {
+		t.Errorf("JSON.Path() error = %#v, want DuplicateKeyError of k", err)
+	}
+}
+
+func BenchmarkJSON_Path(b *testing.B) {
+	json := FromString(jsonStr)
+	for i := 0; i < b.N; i++ {
+		j := *json
+		j.Path("list", 1, "values", 3)
+	}
+}
+
+func BenchmarkJSON_Path_Indexed(b *testing.B) {
+	json := FromString(jsonStr)
+	json.BuildIndex()
+	for i := 0; i < b.N; i++ {
+		j := *json
+		j.Path("list", 1, "values", 3)
+	}
+}
@@ -0,0 +1,79 @@
+package jzon
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+)
+
+// ParseTime parses an String json value to time.Time, the layouts are tried
+// in order and the first success is returned. time.RFC3339Nano is used if
+// no layout is given, it accepts RFC 3339 timestamps with or without
+// fractional seconds.
+func (json *JSON) ParseTime(layouts ...string) (time.Time, error) {
+	var buf [64]byte
+	b, err := json.ParseBytes(buf[:0])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("ParseTime: %v", err)
+	}
+	if len(layouts) == 0 {
+		layouts = []string{time.RFC3339Nano}
+	}
+	s := string(b)
+	for _, layout := range layouts {
+		var t time.Time
+		t, err = time.Parse(layout, s)
+		if err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, err
+}
+
+// ParseUnixTime parses an Number json value which is the time elapsed since
+// January 1, 1970 UTC in unit to time.Time. The unit must be one of
+// time.Second, time.Millisecond, time.Microsecond and time.Nanosecond,
+// fractional number is accepted like 1600000000.5 seconds.
+func (json *JSON) ParseUnixTime(unit time.Duration) (time.Time, error) {
+	switch unit {
+	case time.Second, time.Millisecond, time.Microsecond, time.Nanosecond:
+	default:
+		return time.Time{}, fmt.Errorf("ParseUnixTime: unsupported unit %s", unit)
+	}
+	b, f, err := json.number("ParseUnixTime", "time")
+	if err != nil {
+		return time.Time{}, err
+	}
+	per := int64(time.Second / unit)
+
+	if !contains(f, flagIsFloat) && !contains(f, flagIsScientific) {
+		i, err := parseIntBytes("ParseUnixTime", b)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return time.Unix(i/per, i%per*int64(unit)), nil
+	}
+
+	v, err := parseFloatBytes(b, f)
+	if err != nil {
+		return time.Time{}, numError("ParseUnixTime", b, strconv.ErrSyntax)
+	}
+	v /= float64(per)
+	if math.IsInf(v, 0) || v < math.MinInt64 || v >= math.MaxInt64 {
+		return time.Time{}, numError("ParseUnixTime", b, strconv.ErrRange)
+	}
+	sec := math.Floor(v)
+	return time.Unix(int64(sec), int64(math.Round((v-sec)*1e9))), nil
+}
+
+// ParseDuration parses an String json value to time.Duration,
+// the format is the same as time.ParseDuration, like "1h30m"
+func (json *JSON) ParseDuration() (time.Duration, error) {
+	var buf [64]byte
+	b, err := json.ParseBytes(buf[:0])
+	if err != nil {
+		return 0, fmt.Errorf("ParseDuration: %v", err)
+	}
+	return time.ParseDuration(string(b))
+}
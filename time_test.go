@@ -0,0 +1,94 @@
+package jzon
+
+import (
+	"testing"
+	"time"
+)
+
+func TestJSON_ParseTime(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		layouts []string
+		want    time.Time
+		wantErr bool
+	}{
+		{"1", `"2020-01-02T03:04:05Z"`, nil, time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC), false},
+		{"2", `"2020-01-02T03:04:05.123456789Z"`, nil, time.Date(2020, 1, 2, 3, 4, 5, 123456789, time.UTC), false},
+		{"3", `"2020-01-02"`, nil, time.Time{}, true},
+		{"4", `"2020-01-02"`, []string{time.RFC3339, "2006-01-02"}, time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC), false},
+		{"5", `"2020-01-02T11:04:05+08:00"`, nil, time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC), false},
+		{"6", `1577934245`, nil, time.Time{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FromString(tt.data).ParseTime(tt.layouts...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("JSON.ParseTime() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("JSON.ParseTime() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSON_ParseUnixTime(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		unit    time.Duration
+		want    time.Time
+		wantErr bool
+	}{
+		{"1", `1577934245`, time.Second, time.Unix(1577934245, 0), false},
+		{"2", `1577934245123`, time.Millisecond, time.Unix(1577934245, 123000000), false},
+		{"3", `1577934245123456`, time.Microsecond, time.Unix(1577934245, 123456000), false},
+		{"4", `1577934245123456789`, time.Nanosecond, time.Unix(1577934245, 123456789), false},
+		{"5", `1577934245.5`, time.Second, time.Unix(1577934245, 500000000), false},
+		{"6", `-1500`, time.Millisecond, time.Unix(-2, 500000000), false},
+		{"7", `1.5e3`, time.Millisecond, time.Unix(1, 500000000), false},
+		{"8", `"1577934245"`, time.Second, time.Time{}, true},
+		{"9", `1`, time.Minute, time.Time{}, true},
+		{"10", `1e300`, time.Second, time.Time{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FromString(tt.data).ParseUnixTime(tt.unit)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("JSON.ParseUnixTime() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("JSON.ParseUnixTime() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSON_ParseDuration(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"1", `"1h30m"`, 90 * time.Minute, false},
+		{"2", `"-1.5s"`, -1500 * time.Millisecond, false},
+		{"3", `"1x"`, 0, true},
+		{"4", `1`, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FromString(tt.data).ParseDuration()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("JSON.ParseDuration() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("JSON.ParseDuration() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
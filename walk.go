@@ -0,0 +1,186 @@
+package jzon
+
+import "errors"
+
+// SkipValue is used as a return value from WalkFunc to indicate that
+// the children of the object or array are skipped
+var SkipValue = errors.New("skip this value")
+
+// WalkFunc is called by Walk for every value. The path contains the keys
+// (string) and indexes (int) from the root to v like Path, it is reused
+// by Walk so it must be copied to keep. If WalkFunc returns SkipValue,
+// the children of v are skipped, other errors stop the walk.
+type WalkFunc func(path []interface{}, v *JSON) error
+
+// walkSpan is the end of an object or array, spans are in the order
+// of the opening brackets
+type walkSpan struct {
+	tail int
+	// next is the index of the first span after this value
+	next int
+}
+
+// walkFrame is an object or array being walked
+type walkFrame struct {
+	object bool
+	// needKey is true if the next string is a key of object
+	needKey bool
+	key     string
+	index   int
+}
+
+// Walk checks the json value strictly and then visits every value in
+// depth-first order, objects and arrays are visited before their children.
+// The values are detached views.
+//
+// The value is scanned forward twice with an explicit stack, the first
+// pass finds the end of every object and array, the second pass calls
+// fn. So the cost is linear in the size of value whatever the nesting is,
+// and deeply nested input can not exhaust the goroutine stack.
+func (json *JSON) Walk(fn WalkFunc) error {
+	root, err := json.validView()
+	if err != nil {
+		return err
+	}
+	spans, err := root.walkSpans()
+	if err != nil {
+		return err
+	}
+
+	scan := root.value()
+	var stack []*walkFrame
+	path := make([]interface{}, 0, 16)
+	// k is the index of span of the next object or array
+	k := 0
+	for {
+		if !scan.checkContext() {
+			return scan.err
+		}
+		c, ok := scan.nextToken()
+		if !ok {
+			return SyntaxError{Invalid, scan.offset, scan.data}
+		}
+		head := scan.offset
+		tail := -1
+		switch c {
+		case ',':
+			if top := stack[len(stack)-1]; top.object {
+				top.needKey = true
+			} else {
+				top.index++
+			}
+			scan.offset++
+			continue
+		case ':':
+			scan.offset++
+			continue
+		case '}', ']':
+			stack = stack[:len(stack)-1]
+			scan.offset++
+			if len(stack) == 0 {
+				return nil
+			}
+			continue
+		case '{', '[':
+			tail = spans[k].tail
+		case '"':
+			tail = scan.validStringEnd()
+			if tail == -1 {
+				return scan.err
+			}
+			if n := len(stack); n > 0 && stack[n-1].needKey {
+				stack[n-1].key, _ = unquote(scan.data[head:tail])
+				stack[n-1].needKey = false
+				scan.offset = tail
+				continue
+			}
+		case 't', 'f', 'n':
+			if tail = scan.validLiteralValueEnd(); tail == -1 {
+				return scan.err
+			}
+		default:
+			if tail = scan.unsafeNumberEnd(); tail == -1 {
+				return scan.err
+			}
+		}
+
+		// the path of the value
+		v := root
+		if n := len(stack); n > 0 {
+			top := stack[n-1]
+			if top.object {
+				path = append(path[:n-1], top.key)
+			} else {
+				path = append(path[:n-1], top.index)
+			}
+			v = root.Clone()
+			v.head, v.tail, v.offset = head, tail, head
+		} else {
+			path = path[:0]
+		}
+
+		err := fn(path, v)
+		if err != nil && err != SkipValue {
+			return err
+		}
+		if (c == '{' || c == '[') && err == nil {
+			stack = append(stack, &walkFrame{object: c == '{', needKey: c == '{'})
+			scan.offset = head + 1
+			k++
+			continue
+		}
+		if c == '{' || c == '[' {
+			k = spans[k].next
+		}
+		scan.offset = tail
+		if len(stack) == 0 {
+			return nil
+		}
+	}
+}
+
+// walkSpans scans the valid json value and returns the spans of all
+// objects and arrays in it
+func (json *JSON) walkSpans() ([]walkSpan, error) {
+	scan := json.value()
+	var spans []walkSpan
+	// open is the indexes of spans which are not closed
+	var open []int
+	for {
+		if !scan.checkContext() {
+			return nil, scan.err
+		}
+		c, ok := scan.nextToken()
+		if !ok {
+			return spans, nil
+		}
+		switch c {
+		case '{', '[':
+			open = append(open, len(spans))
+			spans = append(spans, walkSpan{})
+			scan.offset++
+		case '}', ']':
+			i := open[len(open)-1]
+			open = open[:len(open)-1]
+			scan.offset++
+			spans[i] = walkSpan{tail: scan.offset, next: len(spans)}
+			if len(open) == 0 {
+				return spans, nil
+			}
+		case '"':
+			if scan.offset = scan.validStringEnd(); scan.offset == -1 {
+				return nil, scan.err
+			}
+		case ',', ':':
+			scan.offset++
+		case 't', 'f', 'n':
+			if scan.offset = scan.validLiteralValueEnd(); scan.offset == -1 {
+				return nil, scan.err
+			}
+		default:
+			if scan.offset = scan.unsafeNumberEnd(); scan.offset == -1 {
+				return nil, scan.err
+			}
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package jzon
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestJSON_Walk(t *testing.T) {
+	json := FromString(`{"a": 1, "b": [true, {"c": null}], "d": {}, "e": {"f": "g"}}`)
+	var got []string
+	err := json.Walk(func(path []interface{}, v *JSON) error {
+		got = append(got, fmt.Sprintf("%v=%s", path, v))
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		`[]={"a": 1, "b": [true, {"c": null}], "d": {}, "e": {"f": "g"}}`,
+		`[a]=1`,
+		`[b]=[true, {"c": null}]`,
+		`[b 0]=true`,
+		`[b 1]={"c": null}`,
+		`[b 1 c]=null`,
+		`[d]={}`,
+		`[e]={"f": "g"}`,
+		`[e f]="g"`,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("JSON.Walk() = %v, want %v", got, want)
+	}
+
+	// the path can be used by Path
+	err = json.Walk(func(path []interface{}, v *JSON) error {
+		c := json.Clone()
+		if err := c.Path(path...); err != nil {
+			return err
+		}
+		if c.String() != v.String() {
+			return fmt.Errorf("Path(%v) = %v, want %v", path, c, v)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestJSON_Walk_Skip(t *testing.T) {
+	json := FromString(`{"a": {"b": 1}, "c": [1, 2], "d": 3}`)
+	var got []string
+	err := json.Walk(func(path []interface{}, v *JSON) error {
+		got = append(got, fmt.Sprint(path))
+		if v.Kind() == Object && len(path) > 0 {
+			return SkipValue
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"[]", "[a]", "[c]", "[c 0]", "[c 1]", "[d]"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("JSON.Walk() with SkipValue = %v, want %v", got, want)
+	}
+
+	errStop := fmt.Errorf("stop")
+	n := 0
+	err = json.Walk(func(path []interface{}, v *JSON) error {
+		n++
+		if len(path) == 2 {
+			return errStop
+		}
+		return nil
+	})
+	if err != errStop || n != 3 {
+		t.Errorf("JSON.Walk() = %v after %d calls, want %v after 3 calls", err, n, errStop)
+	}
+}
+
+func TestJSON_Walk_Error(t *testing.T) {
+	for _, data := range []string{`{"a": }`, `[1, 2`, ``} {
+		err := FromString(data).Walk(func(path []interface{}, v *JSON) error {
+			t.Errorf("JSON.Walk() of %s calls fn", data)
+			return nil
+		})
+		if err == nil {
+			t.Errorf("JSON.Walk() of %s error = nil, want error", data)
+		}
+	}
+}
+
+func TestJSON_Walk_DeepNesting(t *testing.T) {
+	// the walk is linear, it is quadratic if every level is scanned again
+	n := 100000
+	data := strings.Repeat(`{"a":[`, n) + "1" + strings.Repeat("]}", n)
+	depth, calls := 0, 0
+	var last string
+	done := make(chan error)
+	go func() {
+		done <- FromString(data).Walk(func(path []interface{}, v *JSON) error {
+			calls++
+			if len(path) > depth {
+				depth = len(path)
+				last = fmt.Sprintf("%v %v", path[0], path[len(path)-1])
+			}
+			return nil
+		})
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("JSON.Walk() of %d levels does not return in 5s", 2*n)
+	}
+	if depth != 2*n || calls != 2*n+1 || last != "a 0" {
+		t.Errorf("JSON.Walk() max depth = %v with %v calls and path %v, want %v with %v calls and path a 0", depth, calls, last, 2*n, 2*n+1)
+	}
+
+	// the children of skipped value are not scanned again
+	calls = 0
+	err := FromString(`[` + data + `, [1, [2]], 3]`).Walk(func(path []interface{}, v *JSON) error {
+		calls++
+		if len(path) == 1 && v.Kind() == Object {
+			return SkipValue
+		}
+		if len(path) == 1 && v.Kind() == Array && v.String() != "[1, [2]]" {
+			return fmt.Errorf("path %v = %v, want [1, [2]]", path, v)
+		}
+		return nil
+	})
+	if err != nil || calls != 7 {
+		t.Errorf("JSON.Walk() with SkipValue = %v after %d calls, want nil after 7 calls", err, calls)
+	}
+}
+
+func TestJSON_WalkContext(t *testing.T) {
+	n := 0
+	err := FromString(bigArray).WalkContext(newCancelAfter(3), func(path []interface{}, v *JSON) error {
+		n++
+		return nil
+	})
+	if err != context.Canceled {
+		t.Errorf("JSON.WalkContext() error = %v, want %v", err, context.Canceled)
+	}
+}